@@ -0,0 +1,172 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/arena"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/sgf"
+)
+
+// Tournaments between named agent configurations, run with:
+// go run ./cmd/arena -player "uct:uct,simulations=1000" -player "rave:rave,simulations=1000" -games 100 -sprt
+
+type PlayerFlags []string
+
+func (players *PlayerFlags) String() string {
+	return strings.Join(*players, " ")
+}
+
+func (players *PlayerFlags) Set(value string) error {
+	*players = append(*players, value)
+	return nil
+}
+
+var sprt_names map[arena.SprtResult]string = map[arena.SprtResult]string{
+	arena.SprtContinue: "inconclusive",
+	arena.SprtAcceptH0: "H0 accepted",
+	arena.SprtAcceptH1: "H1 accepted",
+}
+
+func PrintPairings(tournament *arena.Tournament, z float64) {
+	fmt.Printf("\n%-12s %-12s %6s %12s %18s %18s %s\n", "player", "opponent", "games", "W-L-D", "win rate", "elo", "sprt")
+	for _, pairing := range tournament.Pairings {
+		var score arena.Score = pairing.Score
+		var low, high float64 = score.WinRateInterval(z)
+		var status string = "-"
+		if pairing.Sprt != nil {
+			status = fmt.Sprintf("%s (llr %.2f)", sprt_names[pairing.Result], pairing.Sprt.LogLikelihoodRatio(score))
+		}
+		fmt.Printf("%-12s %-12s %6d %12s %5.1f%% [%4.1f, %5.1f] %+6.0f [%+5.0f, %+5.0f] %s\n",
+			tournament.Players[pairing.A].Name, tournament.Players[pairing.B].Name, score.Games(),
+			fmt.Sprintf("%d-%d-%d", score.Wins, score.Losses, score.Draws),
+			100*score.WinRate(), 100*low, 100*high,
+			arena.EloFromWinRate(score.WinRate()), arena.EloFromWinRate(low), arena.EloFromWinRate(high), status)
+	}
+}
+
+func PrintRatings(tournament *arena.Tournament, prior float64, z float64) {
+	var ratings arena.Ratings = tournament.Ratings(prior)
+	var order []int = make([]int, len(tournament.Players))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return ratings.Elo[order[a]] > ratings.Elo[order[b]] })
+
+	// Ratings relative to the first player, so that gauntlets read as gains over the challenger
+	var anchor float64 = ratings.Elo[0]
+	fmt.Printf("\n%4s %-12s %8s %8s %6s\n", "rank", "player", "elo", "+-", "games")
+	for rank, player := range order {
+		var games int = 0
+		for _, score := range tournament.Results[player] {
+			games += score.Games()
+		}
+		fmt.Printf("%4d %-12s %+8.0f %8.0f %6d\n", rank+1, tournament.Players[player].Name, ratings.Elo[player]-anchor, z*ratings.Error[player], games)
+	}
+}
+
+func main() {
+	var players PlayerFlags
+	flag.Var(&players, "player", "player as name:kind[,key=value...], kinds: random, uct, rave, puct (repeat for each player)")
+	var schedule *string = flag.String("schedule", "round-robin", "pairings: round-robin, or gauntlet of the first player against the others")
+	var games *int = flag.Int("games", 20, "maximum number of games per pairing")
+	var size *int = flag.Int("size", 9, "board size")
+	var komi *float64 = flag.Float64("komi", 6.5, "komi")
+	var opening *int = flag.Int("opening", 4, "random moves played before the agents take over")
+	var parallel *int = flag.Int("parallel", 4, "games played at the same time")
+	var seed *int64 = flag.Int64("seed", time.Now().UnixNano(), "seed of the random openings")
+	var use_sprt *bool = flag.Bool("sprt", false, "stop each pairing early once the SPRT concludes")
+	var elo0 *float64 = flag.Float64("elo0", 0, "SPRT null hypothesis, Elo of the player over the opponent")
+	var elo1 *float64 = flag.Float64("elo1", 30, "SPRT alternative hypothesis")
+	var alpha *float64 = flag.Float64("alpha", 0.05, "SPRT false positive rate")
+	var beta *float64 = flag.Float64("beta", 0.05, "SPRT false negative rate")
+	var prior *float64 = flag.Float64("prior", 2, "virtual draws of each player against a player of rating 0, as in BayesElo")
+	var z *float64 = flag.Float64("z", 1.96, "width of the confidence intervals in standard deviations")
+	var sgf_dir *string = flag.String("sgf-dir", "", "directory where the games are saved as SGF files, empty to not save them")
+	flag.Parse()
+
+	if len(players) < 2 {
+		log.Fatal("at least two -player are needed")
+	}
+	var configs []*arena.PlayerConfig
+	for _, spec := range players {
+		config, err := arena.ParsePlayerConfig(spec)
+		if err != nil {
+			log.Fatal(err)
+		}
+		configs = append(configs, config)
+	}
+
+	var pairings []*arena.Pairing
+	switch *schedule {
+	case "round-robin":
+		pairings = arena.RoundRobin(len(configs), *games)
+	case "gauntlet":
+		pairings = arena.Gauntlet(len(configs), *games)
+	default:
+		log.Fatalf("unknown schedule %q, expected round-robin or gauntlet", *schedule)
+	}
+	if *use_sprt {
+		for _, pairing := range pairings {
+			pairing.Sprt = &arena.Sprt{Elo0: *elo0, Elo1: *elo1, Alpha: *alpha, Beta: *beta}
+		}
+	}
+
+	var tournament *arena.Tournament = arena.NewTournament(configs, pairings, arena.Settings{
+		Size:         *size,
+		Komi:         *komi,
+		OpeningMoves: *opening,
+		Parallel:     *parallel,
+		Seed:         *seed,
+	})
+	var start time.Time = time.Now()
+	var played int = 0
+	tournament.OnGame = func(outcome arena.GameOutcome, tournament *arena.Tournament) {
+		played++
+		var black, white string = tournament.Players[outcome.Black].Name, tournament.Players[outcome.White].Name
+		if outcome.Err != nil {
+			fmt.Printf("game %d: %s vs %s failed: %s\n", played, black, white, outcome.Err)
+			return
+		}
+		var winner string = black + " wins"
+		switch outcome.Winner {
+		case environment.White:
+			winner = white + " wins"
+		case environment.Empty:
+			winner = "draw"
+		}
+		if *sgf_dir != "" {
+			var record *sgf.Record = sgf.NewRecordFromGame(environment.NewGame(*size, *size, *komi), outcome.Game)
+			record.PlayerBlack, record.PlayerWhite = black, white
+			var path string = filepath.Join(*sgf_dir, fmt.Sprintf("game-%04d-%s-vs-%s.sgf", played, black, white))
+			if err := record.Save(path); err != nil {
+				fmt.Println("could not save the game:", err)
+			}
+		}
+		var pairing *arena.Pairing = outcome.Pairing
+		fmt.Printf("game %d: %s (B) vs %s (W), %s in %d moves, %s %d-%d-%d %s\n", played, black, white, winner, outcome.Moves,
+			tournament.Players[pairing.A].Name, pairing.Score.Wins, pairing.Score.Losses, pairing.Score.Draws, tournament.Players[pairing.B].Name)
+	}
+
+	fmt.Printf("%d players, %d pairings, %dx%d komi %.1f, seed %d\n", len(configs), len(pairings), *size, *size, *komi, *seed)
+	if *sgf_dir != "" {
+		if err := os.MkdirAll(*sgf_dir, 0755); err != nil {
+			log.Fatal(err)
+		}
+	}
+	var err error = tournament.Run()
+	fmt.Printf("\n%d games in %s\n", played, time.Since(start).Round(time.Second))
+	PrintPairings(tournament, *z)
+	PrintRatings(tournament, *prior, *z)
+	if err != nil {
+		fmt.Println("\nsome games failed:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+)
+
+// A/B benchmarks between agent variants, run with: go run ./cmd/bench -mode rave -games 50
+// Pattern against random playouts, speed and match at equal simulations: go run ./cmd/bench -mode playout -games 50
+// Search speed against the number of routines, run with: go run ./cmd/bench -mode scaling -max-routines 32
+
+func PlayGame(black_agent, white_agent agents.Agent, size int, komi float64) environment.Stone {
+	var game *environment.Game = environment.NewGame(size, size, komi)
+	for !game.IsTerminal() && len(game.MoveHistory) < 4*size*size {
+		var current_agent agents.Agent = black_agent
+		if game.Board.CurrentPlayer == environment.White {
+			current_agent = white_agent
+		}
+		game.PlayAction(current_agent.SelectAction(game.DeepCopy()))
+	}
+	return game.GetWinner()
+}
+
+func RunMatch(name_a, name_b string, new_a, new_b func() agents.Agent, games int, size int, komi float64) {
+	var wins_a int = 0
+	var start time.Time = time.Now()
+	for game_idx := 0; game_idx < games; game_idx++ {
+		// Alternate colors so that neither variant benefits from komi or from playing first
+		var agent_a, agent_b agents.Agent = new_a(), new_b()
+		var a_color environment.Stone = environment.Black
+		var winner environment.Stone
+		if game_idx%2 == 0 {
+			winner = PlayGame(agent_a, agent_b, size, komi)
+		} else {
+			a_color = environment.White
+			winner = PlayGame(agent_b, agent_a, size, komi)
+		}
+		var winner_name string = name_b
+		if winner == a_color {
+			wins_a++
+			winner_name = name_a
+		}
+		fmt.Printf("game %d/%d: %s wins, %s %d - %d %s\n", game_idx+1, games, winner_name, name_a, wins_a, game_idx+1-wins_a, name_b)
+	}
+
+	var win_rate float64 = float64(wins_a) / float64(games)
+	var std_error float64 = math.Sqrt(win_rate * (1 - win_rate) / float64(games))
+	fmt.Printf("%s vs %s: %d games in %s, %s win rate %.1f%% +- %.1f%% (95%%)\n", name_a, name_b, games, time.Since(start).Round(time.Second), name_a, 100*win_rate, 196*std_error)
+}
+
+func RunScaling(max_routines int, simulations int, searches int, size int, komi float64) {
+	var base_speed float64 = 0
+	fmt.Printf("%8s %12s %10s %10s\n", "routines", "simulations/s", "speedup", "efficiency")
+	for _, routines := range agents.ScalingRoutines(max_routines) {
+		var total_simulations int = 0
+		var total_time time.Duration = 0
+		for search := 0; search < searches; search++ {
+			simulations_done, elapsed := agents.TimeSearch(simulations, routines, environment.NewGame(size, size, komi))
+			total_simulations += simulations_done
+			total_time += elapsed
+		}
+		var speed float64 = float64(total_simulations) / total_time.Seconds()
+		if routines == 1 {
+			base_speed = speed
+		}
+		fmt.Printf("%8d %12.0f %9.2fx %9.0f%%\n", routines, speed, speed/base_speed, 100*speed/base_speed/float64(routines))
+	}
+}
+
+func RunPlayouts(policies []string, playouts int, size int, komi float64) {
+	fmt.Printf("%-8s %12s %12s %12s\n", "policy", "playouts/s", "moves", "black wins")
+	for _, name := range policies {
+		policy, _ := agents.NewPlayoutPolicy(name)
+		var moves, black_wins int = 0, 0
+		var start time.Time = time.Now()
+		for playout := 0; playout < playouts; playout++ {
+			var game *environment.Game = environment.NewGame(size, size, komi)
+			for !game.IsTerminal() {
+				game.PlayAction(policy.SelectAction(game))
+			}
+			moves += len(game.MoveHistory)
+			if game.GetWinner() == environment.Black {
+				black_wins++
+			}
+		}
+		var elapsed time.Duration = time.Since(start)
+		fmt.Printf("%-8s %12.0f %12.1f %11.1f%%\n", name, float64(playouts)/elapsed.Seconds(), float64(moves)/float64(playouts), 100*float64(black_wins)/float64(playouts))
+	}
+}
+
+func main() {
+	var mode *string = flag.String("mode", "rave", "benchmark to run: rave, playout or scaling")
+	var games *int = flag.Int("games", 20, "number of games")
+	var size *int = flag.Int("size", 9, "board size")
+	var komi *float64 = flag.Float64("komi", 6.5, "komi")
+	var simulations *int = flag.Int("simulations", 2000, "simulations per move")
+	var routines *int = flag.Int("routines", 4, "search routines per agent")
+	var schedule *string = flag.String("schedule", "hand", "RAVE schedule: hand or mse")
+	var rave_k *float64 = flag.Float64("rave-k", 1000, "equivalence parameter of the hand-selected schedule")
+	var rave_bias *float64 = flag.Float64("rave-bias", 0.1, "bias of the minimum MSE schedule")
+	var rave_exploration *float64 = flag.Float64("rave-exploration", 0.3, "UCT exploration constant with RAVE")
+	var max_routines *int = flag.Int("max-routines", agents.MaxScalingRoutines, "largest number of routines of the scaling benchmark")
+	var searches *int = flag.Int("searches", 5, "searches per number of routines in the scaling benchmark")
+	var playouts *int = flag.Int("playouts", 500, "playouts per policy timed by the playout benchmark")
+	flag.Parse()
+
+	switch *mode {
+	case "rave":
+		var rave_schedule agents.RaveSchedule = agents.NewHandSelectedSchedule(*rave_k)
+		if *schedule == "mse" {
+			rave_schedule = agents.NewMinimumMseSchedule(*rave_bias)
+		}
+		var new_rave func() agents.Agent = func() agents.Agent {
+			return agents.NewRaveAgent(*simulations, *routines, -0.95, agents.NewRaveConfig(rave_schedule, *rave_exploration, 1.0))
+		}
+		var new_uct func() agents.Agent = func() agents.Agent {
+			return agents.NewUctAgent(*simulations, *routines, -0.95)
+		}
+		RunMatch("rave", "uct", new_rave, new_uct, *games, *size, *komi)
+	case "playout":
+		RunPlayouts([]string{"random", "pattern"}, *playouts, *size, *komi)
+		var new_pattern func() agents.Agent = func() agents.Agent {
+			var agent *agents.MctsAgent = agents.NewUctAgent(*simulations, *routines, -0.95)
+			agent.SetPlayoutPolicy(agents.NewPatternPolicy())
+			return agent
+		}
+		var new_random func() agents.Agent = func() agents.Agent {
+			return agents.NewUctAgent(*simulations, *routines, -0.95)
+		}
+		RunMatch("pattern", "random", new_pattern, new_random, *games, *size, *komi)
+	case "scaling":
+		RunScaling(*max_routines, *simulations, *searches, *size, *komi)
+	default:
+		println("Unknown benchmark:", *mode)
+	}
+}
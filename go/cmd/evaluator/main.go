@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/evaluator"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/utils"
+	"google.golang.org/grpc"
+)
+
+// Stand-in for the network evaluation server, run with: go run ./cmd/evaluator -backend influence
+
+func main() {
+	var address *string = flag.String("listen", "unix:///tmp/position_evaluation.sock", "address to listen on")
+	var backend_name *string = flag.String("backend", "influence", "heuristic evaluating the positions: uniform, playout or influence")
+	var playouts *int = flag.Int("playouts", 8, "playouts per position of the playout backend")
+	flag.Parse()
+
+	backend, err := evaluator.NewBackend(*backend_name, *playouts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	listener, err := utils.Listen(*address)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var server *grpc.Server = grpc.NewServer()
+	remote_trainer.RegisterPositionEvaluatorServer(server, evaluator.NewServer(backend))
+
+	var signals chan os.Signal = make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		server.GracefulStop()
+	}()
+
+	fmt.Printf("%s evaluator listening on %s\n", *backend_name, *address)
+	if err := server.Serve(listener); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"log"
 
 	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/ui"
@@ -8,8 +9,22 @@ import (
 )
 
 func main() {
-	var app *ui.App = ui.InitializeApp()
-	if err := ebiten.RunGame(app); err != nil {
+	var settings *ui.GameSettings = ui.NewDefaultGameSettings()
+	flag.StringVar(&settings.Black.Kind, "black", settings.Black.Kind, "black player preselected in the setup menu: human, random, uct or puct")
+	flag.StringVar(&settings.White.Kind, "white", settings.White.Kind, "white player preselected in the setup menu: human, random, uct or puct")
+	var sgf_path *string = flag.String("sgf", "", "SGF file to replay instead of playing a new game")
+	flag.Parse()
+
+	window, err := ui.NewWindow(settings)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *sgf_path != "" {
+		if err := window.OpenReplay(*sgf_path); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := ebiten.RunGame(window); err != nil {
 		log.Fatal(err)
 	}
 }
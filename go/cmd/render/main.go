@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/gif"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/render"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/sgf"
+)
+
+// Images of recorded games without opening a window, run with:
+// go run ./cmd/render -sgf game.sgf -move 40 -out position.png
+// go run ./cmd/render -sgf game.sgf -out game.gif -numbers
+
+func SearchHeatmap(game *environment.Game, simulations int, routines int) [][]float64 {
+	// Visits of a UCT search of the position
+	if game.IsTerminal() {
+		return nil
+	}
+	var agent *agents.MctsAgent = agents.NewUctAgent(simulations, routines, -1)
+	agent.InfoCandidates = game.Board.Height*game.Board.Width + 1
+	agent.InfoPVLength = 1
+	agent.SelectAction(game.DeepCopy())
+	return render.HeatmapFromSearch(agent.LastSearchInfo, game.Board.Height, game.Board.Width)
+}
+
+func main() {
+	var sgf_path *string = flag.String("sgf", "", "SGF file of the game")
+	var out *string = flag.String("out", "board.png", "output file, a PNG of one position or an animated GIF of the game if it ends with .gif")
+	var move *int = flag.Int("move", -1, "number of moves played in the PNG position, -1 for the final position")
+	var size *int = flag.Int("size", 512, "width and height of the image in pixels")
+	var numbers *bool = flag.Bool("numbers", false, "show move numbers on the stones")
+	var coordinates *bool = flag.Bool("coordinates", true, "show the coordinates around the board")
+	var delay *int = flag.Int("delay", 80, "time between the GIF frames in hundredths of a second")
+	var heatmap *int = flag.Int("heatmap", 0, "simulations of a UCT search whose visits are shaded on the board, 0 for none")
+	var routines *int = flag.Int("routines", 4, "routines of the heatmap search")
+	flag.Parse()
+
+	if *sgf_path == "" {
+		log.Fatal("-sgf is required")
+	}
+	record, err := sgf.Load(*sgf_path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	positions, err := record.Positions()
+	if err != nil {
+		log.Fatal(err)
+	}
+	var options render.Options = render.DefaultOptions()
+	options.Size = *size
+	options.MoveNumbers = *numbers
+	options.Coordinates = *coordinates
+	options.FirstMove = len(positions[0].MoveHistory)
+
+	file, err := os.Create(*out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(*out), ".gif") {
+		var heatmaps [][][]float64
+		if *heatmap > 0 {
+			for _, game := range positions {
+				heatmaps = append(heatmaps, SearchHeatmap(game, *heatmap, *routines))
+			}
+		}
+		animation, err := render.RenderGif(positions, options, heatmaps, *delay)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := gif.EncodeAll(file, animation); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%d frames written to %s\n", len(animation.Image), *out)
+		return
+	}
+
+	var index int = len(positions) - 1
+	if *move >= 0 {
+		index = min(*move, len(positions)-1)
+	}
+	var game *environment.Game = positions[index]
+	if *heatmap > 0 {
+		options.Heatmap = SearchHeatmap(game, *heatmap, *routines)
+	}
+	image, err := render.Render(game, options)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := png.Encode(file, image); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("position after %d moves written to %s\n", index, *out)
+}
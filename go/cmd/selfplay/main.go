@@ -0,0 +1,152 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/selfplay"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/trainer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Self-play training data generation, run with: go run ./cmd/selfplay -games 100 -out data/selfplay
+// With a trainer, the games are also streamed to it and the workers follow its latest model:
+// go run ./cmd/selfplay -agent puct -trainer unix:///tmp/trainer.sock -games 1000000
+
+func main() {
+	var games *int = flag.Int("games", 10, "number of games")
+	var parallel *int = flag.Int("parallel", 1, "number of games played at the same time")
+	var size *int = flag.Int("size", 9, "board size")
+	var komi *float64 = flag.Float64("komi", 6.5, "komi")
+	var simulations *int = flag.Int("simulations", 400, "simulations per move")
+	var routines *int = flag.Int("routines", 4, "search routines per game")
+	var agent_kind *string = flag.String("agent", "uct", "search: uct, or puct with the position evaluator")
+	var evaluator *string = flag.String("evaluator", "unix:///tmp/position_evaluation.sock", "address of the position evaluator for puct")
+	var model_version *string = flag.String("model-version", "none", "version of the network recorded in the shards")
+	var out *string = flag.String("out", "selfplay", "output directory of the shards, empty to not write shards")
+	var prefix *string = flag.String("prefix", "", "prefix of the shard files, the start time by default")
+	var shard_size *int = flag.Int("shard-size", 4096, "positions per shard")
+	var trainer_address *string = flag.String("trainer", "", "address of the trainer receiving the games, empty to play offline")
+	var worker_id *string = flag.String("worker-id", "", "name of this worker for the trainer, the host name by default")
+	var model_dir *string = flag.String("model-dir", "", "where the weights sent by the trainer are saved")
+	var model_poll *time.Duration = flag.Duration("model-poll", 30*time.Second, "interval between two checks of the latest model")
+	var seed *int64 = flag.Int64("seed", time.Now().UnixNano(), "seed of the root noise and of the move sampling")
+	flag.Parse()
+
+	var client remote_trainer.PositionEvaluatorClient = nil
+	if *agent_kind == "puct" {
+		conn, err := grpc.NewClient(*evaluator, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			log.Fatal("Error connecting to position evaluation server: ", err)
+		}
+		defer conn.Close()
+		client = remote_trainer.NewPositionEvaluatorClient(conn)
+	}
+	var uploader *trainer.GameUploader = nil
+	var watcher *trainer.ModelWatcher = nil
+	if *trainer_address != "" {
+		conn, err := grpc.NewClient(*trainer_address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			log.Fatal("Error connecting to the trainer: ", err)
+		}
+		defer conn.Close()
+		var trainer_client remote_trainer.TrainerClient = remote_trainer.NewTrainerClient(conn)
+		uploader = trainer.NewGameUploader(trainer_client, 2**parallel)
+		watcher = trainer.NewModelWatcher(trainer_client, *model_poll, *model_dir)
+		watcher.OnUpdate = func(model *remote_trainer.ModelInfo) {
+			fmt.Printf("switching to model %s\n", model.Version)
+		}
+		watcher.Start()
+		defer watcher.Stop()
+		if *worker_id == "" {
+			*worker_id, _ = os.Hostname()
+		}
+	}
+	var current_model_version func() string = func() string {
+		if watcher != nil && watcher.Latest().Version != "" {
+			return watcher.Latest().Version
+		}
+		return *model_version
+	}
+
+	var new_agent func(game_id int, version string) *agents.MctsAgent = func(game_id int, version string) *agents.MctsAgent {
+		var agent *agents.MctsAgent
+		if client != nil {
+			agent = agents.NewPuctAgent(*simulations, *routines, -1, client)
+			agent.Expander.(*agents.PuctExpander).SetModelVersion(version)
+		} else {
+			agent = agents.NewUctAgent(*simulations, *routines, -1)
+		}
+		agent.SetSelfPlay(agents.NewDefaultSelfPlayConfig(*size, *seed+int64(game_id)))
+		return agent
+	}
+
+	if *prefix == "" {
+		*prefix = time.Now().UTC().Format("20060102-150405")
+	}
+	var writer *selfplay.ShardWriter = nil
+	if *out != "" {
+		var err error
+		writer, err = selfplay.NewShardWriter(*out, *prefix, *shard_size, current_model_version(), *komi, *size, *size)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var start time.Time = time.Now()
+	var next_game chan int = make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(*parallel)
+	for worker := 0; worker < *parallel; worker++ {
+		go func() {
+			defer wg.Done()
+			for game_id := range next_game {
+				// A new model is picked up between two games, each game is played by a single version
+				var version string = current_model_version()
+				var agent *agents.MctsAgent = new_agent(game_id, version)
+				examples, game := selfplay.PlayGame(agent, game_id, *size, *size, *komi, 4**size**size)
+				agent.Close()
+				if writer != nil {
+					if err := writer.SetModelVersion(version); err != nil {
+						log.Fatal(err)
+					}
+					if err := writer.Add(examples); err != nil {
+						log.Fatal(err)
+					}
+				}
+				if uploader != nil {
+					uploader.Submit(trainer.NewGameRecord(*worker_id, version, game, examples))
+				}
+				fmt.Printf("game %d/%d: %d moves, result for black %+.0f\n", game_id+1, *games, len(game.MoveHistory), game.GameResult(environment.Black))
+			}
+		}()
+	}
+	for game_id := 0; game_id < *games; game_id++ {
+		next_game <- game_id
+	}
+	close(next_game)
+	wg.Wait()
+
+	fmt.Printf("%d games in %s\n", *games, time.Since(start).Round(time.Second))
+	if writer != nil {
+		if err := writer.Close(); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%d shards written to %s\n", len(writer.Written), *out)
+	}
+	if uploader != nil {
+		response, err := uploader.Close()
+		if err != nil {
+			log.Fatal("Error submitting the games: ", err)
+		}
+		fmt.Printf("trainer received %d games, %d positions\n", response.GamesReceived, response.PositionsReceived)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/trainer"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/utils"
+	"google.golang.org/grpc"
+)
+
+// Local stand-in for the trainer, run with: go run ./cmd/trainerstub -listen unix:///tmp/trainer.sock -out data/received
+
+func main() {
+	var address *string = flag.String("listen", "unix:///tmp/trainer.sock", "address to listen on")
+	var out *string = flag.String("out", "", "directory where the received games are written as shards, empty to only count them")
+	var shard_size *int = flag.Int("shard-size", 4096, "positions per shard")
+	var model_version *string = flag.String("model-version", "", "version of the model served at start, none if empty")
+	var model_path *string = flag.String("model-path", "", "path of the weights of the model served at start")
+	flag.Parse()
+
+	var stub *trainer.StubTrainer = trainer.NewStubTrainer(*out, *shard_size)
+	if *model_version != "" {
+		stub.PublishModel(&remote_trainer.ModelInfo{Version: *model_version, Path: *model_path}, true)
+	}
+
+	listener, err := utils.Listen(*address)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var server *grpc.Server = grpc.NewServer()
+	remote_trainer.RegisterTrainerServer(server, stub)
+
+	// Write the last shards before exiting
+	var signals chan os.Signal = make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		server.GracefulStop()
+	}()
+
+	fmt.Println("trainer stub listening on", *address)
+	if err := server.Serve(listener); err != nil {
+		log.Fatal(err)
+	}
+	if err := stub.Close(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("received %d games, %d positions, %d match results\n", stub.Games, stub.Positions, len(stub.Matches))
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/arena"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/tui"
+)
+
+// Games in the terminal, also over SSH where there is no window, run with:
+// go run ./cmd/tui -black human -white "uct,simulations=2000"
+// ssh -t host ./tui -black "rave,simulations=5000" -white "uct,simulations=5000"
+
+func main() {
+	var black *string = flag.String("black", "human", "black player: human, or kind[,key=value...] as in the arena, kinds: random, uct, rave, puct")
+	var white *string = flag.String("white", "uct,simulations=2000", "white player: human, or kind[,key=value...] as in the arena")
+	var size *int = flag.Int("size", 9, "board size")
+	var komi *float64 = flag.Float64("komi", 6.5, "komi")
+	var handicap *int = flag.Int("handicap", 0, "handicap stones of black")
+	var rules_name *string = flag.String("rules", "chinese", "scoring rules: chinese or japanese")
+	flag.Parse()
+
+	rules, err := environment.ParseRuleset(*rules_name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	black_agent, err := arena.NewInteractivePlayer(*black, "black")
+	if err != nil {
+		log.Fatal(err)
+	}
+	white_agent, err := arena.NewInteractivePlayer(*white, "white")
+	if err != nil {
+		log.Fatal(err)
+	}
+	var game *environment.Game = environment.NewHandicapGame(*size, *komi, *handicap, rules)
+	var session *tui.Session = tui.NewSession(game, black_agent, white_agent, *black, *white)
+
+	terminal, err := tui.OpenTerminal()
+	if err != nil {
+		log.Fatal(err)
+	}
+	session.Run(terminal)
+	terminal.Close()
+
+	// The result stays in the shell once the alternate screen is gone
+	game = session.Game.Get()
+	if game.IsTerminal() {
+		fmt.Println(tui.Result(game))
+	}
+	fmt.Println(len(game.MoveHistory), "moves played")
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/arena"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/web"
+)
+
+// Games in the browser, any number of spectators can open the page, run with:
+// go run ./cmd/web -black human -white "uct,simulations=2000"
+// go run ./cmd/web -black "puct,simulations=800" -white "puct,simulations=800" -games 0
+// then open http://localhost:8080
+
+func main() {
+	var black *string = flag.String("black", "human", "black player: human, or kind[,key=value...] as in the arena, kinds: random, uct, rave, puct")
+	var white *string = flag.String("white", "uct,simulations=2000", "white player: human, or kind[,key=value...] as in the arena")
+	var size *int = flag.Int("size", 9, "board size")
+	var komi *float64 = flag.Float64("komi", 6.5, "komi")
+	var handicap *int = flag.Int("handicap", 0, "handicap stones of black")
+	var rules_name *string = flag.String("rules", "chinese", "scoring rules: chinese or japanese")
+	var games *int = flag.Int("games", 1, "games played one after the other, 0 to play until the server stops")
+	var address *string = flag.String("address", "localhost:8080", "address of the HTTP server, localhost only by default")
+	flag.Parse()
+
+	rules, err := environment.ParseRuleset(*rules_name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	black_agent, err := arena.NewInteractivePlayer(*black, "black")
+	if err != nil {
+		log.Fatal(err)
+	}
+	white_agent, err := arena.NewInteractivePlayer(*white, "white")
+	if err != nil {
+		log.Fatal(err)
+	}
+	var new_game func() *environment.Game = func() *environment.Game {
+		return environment.NewHandicapGame(*size, *komi, *handicap, rules)
+	}
+	var match *web.Match = web.NewMatch(new_game, *games, black_agent, white_agent, *black, *white)
+	var server *web.Server = web.NewServer(match)
+	go match.Run()
+
+	log.Printf("serving on http://%s", *address)
+	log.Fatal(server.ListenAndServe(*address))
+}
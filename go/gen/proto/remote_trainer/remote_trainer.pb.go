@@ -21,10 +21,17 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Points are indexed row by row (i * width + j), the pass move comes after the last point
 type EvaluatePositionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	X             int64                  `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
-	Y             int64                  `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+	Height        int32                  `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Width         int32                  `protobuf:"varint,2,opt,name=width,proto3" json:"width,omitempty"`
+	NumPlanes     int32                  `protobuf:"varint,3,opt,name=num_planes,json=numPlanes,proto3" json:"num_planes,omitempty"`
+	Planes        []float32              `protobuf:"fixed32,4,rep,packed,name=planes,proto3" json:"planes,omitempty"`                       // num_planes x height x width feature planes, from the point of view of the player to move
+	LegalMask     []bool                 `protobuf:"varint,5,rep,packed,name=legal_mask,json=legalMask,proto3" json:"legal_mask,omitempty"` // height x width + 1 flags, the legal moves of the player to move
+	Symmetry      int32                  `protobuf:"varint,6,opt,name=symmetry,proto3" json:"symmetry,omitempty"`                           // Symmetry of the board (0 to 7) already applied to the planes and the mask
+	WantOwnership bool                   `protobuf:"varint,7,opt,name=want_ownership,json=wantOwnership,proto3" json:"want_ownership,omitempty"`
+	ModelVersion  string                 `protobuf:"bytes,8,opt,name=model_version,json=modelVersion,proto3" json:"model_version,omitempty"` // Version of the network to use, the latest one if empty
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -59,23 +66,68 @@ func (*EvaluatePositionRequest) Descriptor() ([]byte, []int) {
 	return file_proto_remote_trainer_proto_rawDescGZIP(), []int{0}
 }
 
-func (x *EvaluatePositionRequest) GetX() int64 {
+func (x *EvaluatePositionRequest) GetHeight() int32 {
 	if x != nil {
-		return x.X
+		return x.Height
 	}
 	return 0
 }
 
-func (x *EvaluatePositionRequest) GetY() int64 {
+func (x *EvaluatePositionRequest) GetWidth() int32 {
 	if x != nil {
-		return x.Y
+		return x.Width
 	}
 	return 0
 }
 
+func (x *EvaluatePositionRequest) GetNumPlanes() int32 {
+	if x != nil {
+		return x.NumPlanes
+	}
+	return 0
+}
+
+func (x *EvaluatePositionRequest) GetPlanes() []float32 {
+	if x != nil {
+		return x.Planes
+	}
+	return nil
+}
+
+func (x *EvaluatePositionRequest) GetLegalMask() []bool {
+	if x != nil {
+		return x.LegalMask
+	}
+	return nil
+}
+
+func (x *EvaluatePositionRequest) GetSymmetry() int32 {
+	if x != nil {
+		return x.Symmetry
+	}
+	return 0
+}
+
+func (x *EvaluatePositionRequest) GetWantOwnership() bool {
+	if x != nil {
+		return x.WantOwnership
+	}
+	return false
+}
+
+func (x *EvaluatePositionRequest) GetModelVersion() string {
+	if x != nil {
+		return x.ModelVersion
+	}
+	return ""
+}
+
 type EvaluatePositionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Z             int64                  `protobuf:"varint,1,opt,name=z,proto3" json:"z,omitempty"`
+	Policy        []float32              `protobuf:"fixed32,1,rep,packed,name=policy,proto3" json:"policy,omitempty"`       // height x width + 1 move probabilities, in the frame of the symmetric planes
+	Value         float32                `protobuf:"fixed32,2,opt,name=value,proto3" json:"value,omitempty"`                // Expected result for the player to move, in [-1, 1]
+	Score         *float32               `protobuf:"fixed32,3,opt,name=score,proto3,oneof" json:"score,omitempty"`          // Expected score lead of the player to move
+	Ownership     []float32              `protobuf:"fixed32,4,rep,packed,name=ownership,proto3" json:"ownership,omitempty"` // height x width ownership in [-1, 1] for the player to move, empty if not requested
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -110,25 +162,633 @@ func (*EvaluatePositionResponse) Descriptor() ([]byte, []int) {
 	return file_proto_remote_trainer_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *EvaluatePositionResponse) GetZ() int64 {
+func (x *EvaluatePositionResponse) GetPolicy() []float32 {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+func (x *EvaluatePositionResponse) GetValue() float32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *EvaluatePositionResponse) GetScore() float32 {
+	if x != nil && x.Score != nil {
+		return *x.Score
+	}
+	return 0
+}
+
+func (x *EvaluatePositionResponse) GetOwnership() []float32 {
+	if x != nil {
+		return x.Ownership
+	}
+	return nil
+}
+
+type EvaluatePositionBatchRequest struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Positions     []*EvaluatePositionRequest `protobuf:"bytes,1,rep,name=positions,proto3" json:"positions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluatePositionBatchRequest) Reset() {
+	*x = EvaluatePositionBatchRequest{}
+	mi := &file_proto_remote_trainer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluatePositionBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluatePositionBatchRequest) ProtoMessage() {}
+
+func (x *EvaluatePositionBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_remote_trainer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluatePositionBatchRequest.ProtoReflect.Descriptor instead.
+func (*EvaluatePositionBatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_remote_trainer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *EvaluatePositionBatchRequest) GetPositions() []*EvaluatePositionRequest {
+	if x != nil {
+		return x.Positions
+	}
+	return nil
+}
+
+type EvaluatePositionBatchResponse struct {
+	state         protoimpl.MessageState      `protogen:"open.v1"`
+	Evaluations   []*EvaluatePositionResponse `protobuf:"bytes,1,rep,name=evaluations,proto3" json:"evaluations,omitempty"` // In the order of the positions
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluatePositionBatchResponse) Reset() {
+	*x = EvaluatePositionBatchResponse{}
+	mi := &file_proto_remote_trainer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluatePositionBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluatePositionBatchResponse) ProtoMessage() {}
+
+func (x *EvaluatePositionBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_remote_trainer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluatePositionBatchResponse.ProtoReflect.Descriptor instead.
+func (*EvaluatePositionBatchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_remote_trainer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *EvaluatePositionBatchResponse) GetEvaluations() []*EvaluatePositionResponse {
+	if x != nil {
+		return x.Evaluations
+	}
+	return nil
+}
+
+type TrainingPosition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Planes        []float32              `protobuf:"fixed32,1,rep,packed,name=planes,proto3" json:"planes,omitempty"` // num_planes x height x width, as in EvaluatePositionRequest
+	Policy        []float32              `protobuf:"fixed32,2,rep,packed,name=policy,proto3" json:"policy,omitempty"` // height x width + 1 root visit distribution
+	Value         float32                `protobuf:"fixed32,3,opt,name=value,proto3" json:"value,omitempty"`          // Final result of the game for the player to move
+	MoveNumber    int32                  `protobuf:"varint,4,opt,name=move_number,json=moveNumber,proto3" json:"move_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrainingPosition) Reset() {
+	*x = TrainingPosition{}
+	mi := &file_proto_remote_trainer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrainingPosition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrainingPosition) ProtoMessage() {}
+
+func (x *TrainingPosition) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_remote_trainer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrainingPosition.ProtoReflect.Descriptor instead.
+func (*TrainingPosition) Descriptor() ([]byte, []int) {
+	return file_proto_remote_trainer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TrainingPosition) GetPlanes() []float32 {
+	if x != nil {
+		return x.Planes
+	}
+	return nil
+}
+
+func (x *TrainingPosition) GetPolicy() []float32 {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+func (x *TrainingPosition) GetValue() float32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *TrainingPosition) GetMoveNumber() int32 {
+	if x != nil {
+		return x.MoveNumber
+	}
+	return 0
+}
+
+type GameRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	ModelVersion  string                 `protobuf:"bytes,2,opt,name=model_version,json=modelVersion,proto3" json:"model_version,omitempty"` // Version of the network that played the game
+	Height        int32                  `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	Width         int32                  `protobuf:"varint,4,opt,name=width,proto3" json:"width,omitempty"`
+	Komi          float32                `protobuf:"fixed32,5,opt,name=komi,proto3" json:"komi,omitempty"`
+	NumPlanes     int32                  `protobuf:"varint,6,opt,name=num_planes,json=numPlanes,proto3" json:"num_planes,omitempty"`
+	BlackResult   float32                `protobuf:"fixed32,7,opt,name=black_result,json=blackResult,proto3" json:"black_result,omitempty"` // Final result of the game for black: 1 win, -1 loss, 0 draw
+	Positions     []*TrainingPosition    `protobuf:"bytes,8,rep,name=positions,proto3" json:"positions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameRecord) Reset() {
+	*x = GameRecord{}
+	mi := &file_proto_remote_trainer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameRecord) ProtoMessage() {}
+
+func (x *GameRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_remote_trainer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameRecord.ProtoReflect.Descriptor instead.
+func (*GameRecord) Descriptor() ([]byte, []int) {
+	return file_proto_remote_trainer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GameRecord) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *GameRecord) GetModelVersion() string {
+	if x != nil {
+		return x.ModelVersion
+	}
+	return ""
+}
+
+func (x *GameRecord) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *GameRecord) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *GameRecord) GetKomi() float32 {
+	if x != nil {
+		return x.Komi
+	}
+	return 0
+}
+
+func (x *GameRecord) GetNumPlanes() int32 {
+	if x != nil {
+		return x.NumPlanes
+	}
+	return 0
+}
+
+func (x *GameRecord) GetBlackResult() float32 {
+	if x != nil {
+		return x.BlackResult
+	}
+	return 0
+}
+
+func (x *GameRecord) GetPositions() []*TrainingPosition {
+	if x != nil {
+		return x.Positions
+	}
+	return nil
+}
+
+type SubmitGamesResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	GamesReceived     int64                  `protobuf:"varint,1,opt,name=games_received,json=gamesReceived,proto3" json:"games_received,omitempty"`
+	PositionsReceived int64                  `protobuf:"varint,2,opt,name=positions_received,json=positionsReceived,proto3" json:"positions_received,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SubmitGamesResponse) Reset() {
+	*x = SubmitGamesResponse{}
+	mi := &file_proto_remote_trainer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitGamesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitGamesResponse) ProtoMessage() {}
+
+func (x *SubmitGamesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_remote_trainer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitGamesResponse.ProtoReflect.Descriptor instead.
+func (*SubmitGamesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_remote_trainer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SubmitGamesResponse) GetGamesReceived() int64 {
+	if x != nil {
+		return x.GamesReceived
+	}
+	return 0
+}
+
+func (x *SubmitGamesResponse) GetPositionsReceived() int64 {
+	if x != nil {
+		return x.PositionsReceived
+	}
+	return 0
+}
+
+type GetLatestModelRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CurrentVersion string                 `protobuf:"bytes,1,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"` // The weights are not sent again when the latest version is the current one
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetLatestModelRequest) Reset() {
+	*x = GetLatestModelRequest{}
+	mi := &file_proto_remote_trainer_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatestModelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestModelRequest) ProtoMessage() {}
+
+func (x *GetLatestModelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_remote_trainer_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestModelRequest.ProtoReflect.Descriptor instead.
+func (*GetLatestModelRequest) Descriptor() ([]byte, []int) {
+	return file_proto_remote_trainer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetLatestModelRequest) GetCurrentVersion() string {
+	if x != nil {
+		return x.CurrentVersion
+	}
+	return ""
+}
+
+type ModelInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Weights       []byte                 `protobuf:"bytes,2,opt,name=weights,proto3" json:"weights,omitempty"` // Serialized network, empty if unchanged or if the model is shared through path
+	Path          string                 `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`       // Location of the weights on a filesystem shared with the workers
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModelInfo) Reset() {
+	*x = ModelInfo{}
+	mi := &file_proto_remote_trainer_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModelInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModelInfo) ProtoMessage() {}
+
+func (x *ModelInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_remote_trainer_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModelInfo.ProtoReflect.Descriptor instead.
+func (*ModelInfo) Descriptor() ([]byte, []int) {
+	return file_proto_remote_trainer_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ModelInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *ModelInfo) GetWeights() []byte {
 	if x != nil {
-		return x.Z
+		return x.Weights
+	}
+	return nil
+}
+
+func (x *ModelInfo) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type MatchResult struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	CandidateVersion string                 `protobuf:"bytes,1,opt,name=candidate_version,json=candidateVersion,proto3" json:"candidate_version,omitempty"`
+	ReferenceVersion string                 `protobuf:"bytes,2,opt,name=reference_version,json=referenceVersion,proto3" json:"reference_version,omitempty"`
+	Wins             int32                  `protobuf:"varint,3,opt,name=wins,proto3" json:"wins,omitempty"` // Games won by the candidate
+	Losses           int32                  `protobuf:"varint,4,opt,name=losses,proto3" json:"losses,omitempty"`
+	Draws            int32                  `protobuf:"varint,5,opt,name=draws,proto3" json:"draws,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *MatchResult) Reset() {
+	*x = MatchResult{}
+	mi := &file_proto_remote_trainer_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchResult) ProtoMessage() {}
+
+func (x *MatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_remote_trainer_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchResult.ProtoReflect.Descriptor instead.
+func (*MatchResult) Descriptor() ([]byte, []int) {
+	return file_proto_remote_trainer_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *MatchResult) GetCandidateVersion() string {
+	if x != nil {
+		return x.CandidateVersion
+	}
+	return ""
+}
+
+func (x *MatchResult) GetReferenceVersion() string {
+	if x != nil {
+		return x.ReferenceVersion
+	}
+	return ""
+}
+
+func (x *MatchResult) GetWins() int32 {
+	if x != nil {
+		return x.Wins
+	}
+	return 0
+}
+
+func (x *MatchResult) GetLosses() int32 {
+	if x != nil {
+		return x.Losses
 	}
 	return 0
 }
 
+func (x *MatchResult) GetDraws() int32 {
+	if x != nil {
+		return x.Draws
+	}
+	return 0
+}
+
+type ReportMatchResultResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Promoted      bool                   `protobuf:"varint,1,opt,name=promoted,proto3" json:"promoted,omitempty"` // The candidate became the latest model
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportMatchResultResponse) Reset() {
+	*x = ReportMatchResultResponse{}
+	mi := &file_proto_remote_trainer_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportMatchResultResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportMatchResultResponse) ProtoMessage() {}
+
+func (x *ReportMatchResultResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_remote_trainer_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportMatchResultResponse.ProtoReflect.Descriptor instead.
+func (*ReportMatchResultResponse) Descriptor() ([]byte, []int) {
+	return file_proto_remote_trainer_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ReportMatchResultResponse) GetPromoted() bool {
+	if x != nil {
+		return x.Promoted
+	}
+	return false
+}
+
 var File_proto_remote_trainer_proto protoreflect.FileDescriptor
 
 const file_proto_remote_trainer_proto_rawDesc = "" +
 	"\n" +
-	"\x1aproto/remote_trainer.proto\x12\x0eremote_trainer\"5\n" +
-	"\x17EvaluatePositionRequest\x12\f\n" +
-	"\x01x\x18\x01 \x01(\x03R\x01x\x12\f\n" +
-	"\x01y\x18\x02 \x01(\x03R\x01y\"(\n" +
-	"\x18EvaluatePositionResponse\x12\f\n" +
-	"\x01z\x18\x01 \x01(\x03R\x01z2z\n" +
+	"\x1aproto/remote_trainer.proto\x12\x0eremote_trainer\"\x85\x02\n" +
+	"\x17EvaluatePositionRequest\x12\x16\n" +
+	"\x06height\x18\x01 \x01(\x05R\x06height\x12\x14\n" +
+	"\x05width\x18\x02 \x01(\x05R\x05width\x12\x1d\n" +
+	"\n" +
+	"num_planes\x18\x03 \x01(\x05R\tnumPlanes\x12\x16\n" +
+	"\x06planes\x18\x04 \x03(\x02R\x06planes\x12\x1d\n" +
+	"\n" +
+	"legal_mask\x18\x05 \x03(\bR\tlegalMask\x12\x1a\n" +
+	"\bsymmetry\x18\x06 \x01(\x05R\bsymmetry\x12%\n" +
+	"\x0ewant_ownership\x18\a \x01(\bR\rwantOwnership\x12#\n" +
+	"\rmodel_version\x18\b \x01(\tR\fmodelVersion\"\x8b\x01\n" +
+	"\x18EvaluatePositionResponse\x12\x16\n" +
+	"\x06policy\x18\x01 \x03(\x02R\x06policy\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x02R\x05value\x12\x19\n" +
+	"\x05score\x18\x03 \x01(\x02H\x00R\x05score\x88\x01\x01\x12\x1c\n" +
+	"\townership\x18\x04 \x03(\x02R\townershipB\b\n" +
+	"\x06_score\"e\n" +
+	"\x1cEvaluatePositionBatchRequest\x12E\n" +
+	"\tpositions\x18\x01 \x03(\v2'.remote_trainer.EvaluatePositionRequestR\tpositions\"k\n" +
+	"\x1dEvaluatePositionBatchResponse\x12J\n" +
+	"\vevaluations\x18\x01 \x03(\v2(.remote_trainer.EvaluatePositionResponseR\vevaluations\"y\n" +
+	"\x10TrainingPosition\x12\x16\n" +
+	"\x06planes\x18\x01 \x03(\x02R\x06planes\x12\x16\n" +
+	"\x06policy\x18\x02 \x03(\x02R\x06policy\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x02R\x05value\x12\x1f\n" +
+	"\vmove_number\x18\x04 \x01(\x05R\n" +
+	"moveNumber\"\x92\x02\n" +
+	"\n" +
+	"GameRecord\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12#\n" +
+	"\rmodel_version\x18\x02 \x01(\tR\fmodelVersion\x12\x16\n" +
+	"\x06height\x18\x03 \x01(\x05R\x06height\x12\x14\n" +
+	"\x05width\x18\x04 \x01(\x05R\x05width\x12\x12\n" +
+	"\x04komi\x18\x05 \x01(\x02R\x04komi\x12\x1d\n" +
+	"\n" +
+	"num_planes\x18\x06 \x01(\x05R\tnumPlanes\x12!\n" +
+	"\fblack_result\x18\a \x01(\x02R\vblackResult\x12>\n" +
+	"\tpositions\x18\b \x03(\v2 .remote_trainer.TrainingPositionR\tpositions\"k\n" +
+	"\x13SubmitGamesResponse\x12%\n" +
+	"\x0egames_received\x18\x01 \x01(\x03R\rgamesReceived\x12-\n" +
+	"\x12positions_received\x18\x02 \x01(\x03R\x11positionsReceived\"@\n" +
+	"\x15GetLatestModelRequest\x12'\n" +
+	"\x0fcurrent_version\x18\x01 \x01(\tR\x0ecurrentVersion\"S\n" +
+	"\tModelInfo\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x18\n" +
+	"\aweights\x18\x02 \x01(\fR\aweights\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\"\xa9\x01\n" +
+	"\vMatchResult\x12+\n" +
+	"\x11candidate_version\x18\x01 \x01(\tR\x10candidateVersion\x12+\n" +
+	"\x11reference_version\x18\x02 \x01(\tR\x10referenceVersion\x12\x12\n" +
+	"\x04wins\x18\x03 \x01(\x05R\x04wins\x12\x16\n" +
+	"\x06losses\x18\x04 \x01(\x05R\x06losses\x12\x14\n" +
+	"\x05draws\x18\x05 \x01(\x05R\x05draws\"7\n" +
+	"\x19ReportMatchResultResponse\x12\x1a\n" +
+	"\bpromoted\x18\x01 \x01(\bR\bpromoted2\xf0\x01\n" +
 	"\x11PositionEvaluator\x12e\n" +
-	"\x10EvaluatePosition\x12'.remote_trainer.EvaluatePositionRequest\x1a(.remote_trainer.EvaluatePositionResponseB\x11Z\x0f/remote_trainerb\x06proto3"
+	"\x10EvaluatePosition\x12'.remote_trainer.EvaluatePositionRequest\x1a(.remote_trainer.EvaluatePositionResponse\x12t\n" +
+	"\x15EvaluatePositionBatch\x12,.remote_trainer.EvaluatePositionBatchRequest\x1a-.remote_trainer.EvaluatePositionBatchResponse2\x8c\x02\n" +
+	"\aTrainer\x12P\n" +
+	"\vSubmitGames\x12\x1a.remote_trainer.GameRecord\x1a#.remote_trainer.SubmitGamesResponse(\x01\x12R\n" +
+	"\x0eGetLatestModel\x12%.remote_trainer.GetLatestModelRequest\x1a\x19.remote_trainer.ModelInfo\x12[\n" +
+	"\x11ReportMatchResult\x12\x1b.remote_trainer.MatchResult\x1a).remote_trainer.ReportMatchResultResponseB\x11Z\x0f/remote_trainerb\x06proto3"
 
 var (
 	file_proto_remote_trainer_proto_rawDescOnce sync.Once
@@ -142,19 +802,39 @@ func file_proto_remote_trainer_proto_rawDescGZIP() []byte {
 	return file_proto_remote_trainer_proto_rawDescData
 }
 
-var file_proto_remote_trainer_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_remote_trainer_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_proto_remote_trainer_proto_goTypes = []any{
-	(*EvaluatePositionRequest)(nil),  // 0: remote_trainer.EvaluatePositionRequest
-	(*EvaluatePositionResponse)(nil), // 1: remote_trainer.EvaluatePositionResponse
+	(*EvaluatePositionRequest)(nil),       // 0: remote_trainer.EvaluatePositionRequest
+	(*EvaluatePositionResponse)(nil),      // 1: remote_trainer.EvaluatePositionResponse
+	(*EvaluatePositionBatchRequest)(nil),  // 2: remote_trainer.EvaluatePositionBatchRequest
+	(*EvaluatePositionBatchResponse)(nil), // 3: remote_trainer.EvaluatePositionBatchResponse
+	(*TrainingPosition)(nil),              // 4: remote_trainer.TrainingPosition
+	(*GameRecord)(nil),                    // 5: remote_trainer.GameRecord
+	(*SubmitGamesResponse)(nil),           // 6: remote_trainer.SubmitGamesResponse
+	(*GetLatestModelRequest)(nil),         // 7: remote_trainer.GetLatestModelRequest
+	(*ModelInfo)(nil),                     // 8: remote_trainer.ModelInfo
+	(*MatchResult)(nil),                   // 9: remote_trainer.MatchResult
+	(*ReportMatchResultResponse)(nil),     // 10: remote_trainer.ReportMatchResultResponse
 }
 var file_proto_remote_trainer_proto_depIdxs = []int32{
-	0, // 0: remote_trainer.PositionEvaluator.EvaluatePosition:input_type -> remote_trainer.EvaluatePositionRequest
-	1, // 1: remote_trainer.PositionEvaluator.EvaluatePosition:output_type -> remote_trainer.EvaluatePositionResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: remote_trainer.EvaluatePositionBatchRequest.positions:type_name -> remote_trainer.EvaluatePositionRequest
+	1,  // 1: remote_trainer.EvaluatePositionBatchResponse.evaluations:type_name -> remote_trainer.EvaluatePositionResponse
+	4,  // 2: remote_trainer.GameRecord.positions:type_name -> remote_trainer.TrainingPosition
+	0,  // 3: remote_trainer.PositionEvaluator.EvaluatePosition:input_type -> remote_trainer.EvaluatePositionRequest
+	2,  // 4: remote_trainer.PositionEvaluator.EvaluatePositionBatch:input_type -> remote_trainer.EvaluatePositionBatchRequest
+	5,  // 5: remote_trainer.Trainer.SubmitGames:input_type -> remote_trainer.GameRecord
+	7,  // 6: remote_trainer.Trainer.GetLatestModel:input_type -> remote_trainer.GetLatestModelRequest
+	9,  // 7: remote_trainer.Trainer.ReportMatchResult:input_type -> remote_trainer.MatchResult
+	1,  // 8: remote_trainer.PositionEvaluator.EvaluatePosition:output_type -> remote_trainer.EvaluatePositionResponse
+	3,  // 9: remote_trainer.PositionEvaluator.EvaluatePositionBatch:output_type -> remote_trainer.EvaluatePositionBatchResponse
+	6,  // 10: remote_trainer.Trainer.SubmitGames:output_type -> remote_trainer.SubmitGamesResponse
+	8,  // 11: remote_trainer.Trainer.GetLatestModel:output_type -> remote_trainer.ModelInfo
+	10, // 12: remote_trainer.Trainer.ReportMatchResult:output_type -> remote_trainer.ReportMatchResultResponse
+	8,  // [8:13] is the sub-list for method output_type
+	3,  // [3:8] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_proto_remote_trainer_proto_init() }
@@ -162,15 +842,16 @@ func file_proto_remote_trainer_proto_init() {
 	if File_proto_remote_trainer_proto != nil {
 		return
 	}
+	file_proto_remote_trainer_proto_msgTypes[1].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_remote_trainer_proto_rawDesc), len(file_proto_remote_trainer_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   11,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_proto_remote_trainer_proto_goTypes,
 		DependencyIndexes: file_proto_remote_trainer_proto_depIdxs,
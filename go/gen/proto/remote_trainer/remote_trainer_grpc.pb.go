@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PositionEvaluator_EvaluatePosition_FullMethodName = "/remote_trainer.PositionEvaluator/EvaluatePosition"
+	PositionEvaluator_EvaluatePosition_FullMethodName      = "/remote_trainer.PositionEvaluator/EvaluatePosition"
+	PositionEvaluator_EvaluatePositionBatch_FullMethodName = "/remote_trainer.PositionEvaluator/EvaluatePositionBatch"
 )
 
 // PositionEvaluatorClient is the client API for PositionEvaluator service.
@@ -27,6 +28,7 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type PositionEvaluatorClient interface {
 	EvaluatePosition(ctx context.Context, in *EvaluatePositionRequest, opts ...grpc.CallOption) (*EvaluatePositionResponse, error)
+	EvaluatePositionBatch(ctx context.Context, in *EvaluatePositionBatchRequest, opts ...grpc.CallOption) (*EvaluatePositionBatchResponse, error)
 }
 
 type positionEvaluatorClient struct {
@@ -47,11 +49,22 @@ func (c *positionEvaluatorClient) EvaluatePosition(ctx context.Context, in *Eval
 	return out, nil
 }
 
+func (c *positionEvaluatorClient) EvaluatePositionBatch(ctx context.Context, in *EvaluatePositionBatchRequest, opts ...grpc.CallOption) (*EvaluatePositionBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EvaluatePositionBatchResponse)
+	err := c.cc.Invoke(ctx, PositionEvaluator_EvaluatePositionBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PositionEvaluatorServer is the server API for PositionEvaluator service.
 // All implementations must embed UnimplementedPositionEvaluatorServer
 // for forward compatibility.
 type PositionEvaluatorServer interface {
 	EvaluatePosition(context.Context, *EvaluatePositionRequest) (*EvaluatePositionResponse, error)
+	EvaluatePositionBatch(context.Context, *EvaluatePositionBatchRequest) (*EvaluatePositionBatchResponse, error)
 	mustEmbedUnimplementedPositionEvaluatorServer()
 }
 
@@ -65,6 +78,9 @@ type UnimplementedPositionEvaluatorServer struct{}
 func (UnimplementedPositionEvaluatorServer) EvaluatePosition(context.Context, *EvaluatePositionRequest) (*EvaluatePositionResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method EvaluatePosition not implemented")
 }
+func (UnimplementedPositionEvaluatorServer) EvaluatePositionBatch(context.Context, *EvaluatePositionBatchRequest) (*EvaluatePositionBatchResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EvaluatePositionBatch not implemented")
+}
 func (UnimplementedPositionEvaluatorServer) mustEmbedUnimplementedPositionEvaluatorServer() {}
 func (UnimplementedPositionEvaluatorServer) testEmbeddedByValue()                           {}
 
@@ -104,6 +120,24 @@ func _PositionEvaluator_EvaluatePosition_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PositionEvaluator_EvaluatePositionBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluatePositionBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PositionEvaluatorServer).EvaluatePositionBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PositionEvaluator_EvaluatePositionBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PositionEvaluatorServer).EvaluatePositionBatch(ctx, req.(*EvaluatePositionBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PositionEvaluator_ServiceDesc is the grpc.ServiceDesc for PositionEvaluator service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,7 +149,187 @@ var PositionEvaluator_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "EvaluatePosition",
 			Handler:    _PositionEvaluator_EvaluatePosition_Handler,
 		},
+		{
+			MethodName: "EvaluatePositionBatch",
+			Handler:    _PositionEvaluator_EvaluatePositionBatch_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/remote_trainer.proto",
 }
+
+const (
+	Trainer_SubmitGames_FullMethodName       = "/remote_trainer.Trainer/SubmitGames"
+	Trainer_GetLatestModel_FullMethodName    = "/remote_trainer.Trainer/GetLatestModel"
+	Trainer_ReportMatchResult_FullMethodName = "/remote_trainer.Trainer/ReportMatchResult"
+)
+
+// TrainerClient is the client API for Trainer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// The trainer receives the self-play games, trains the network and publishes its versions
+type TrainerClient interface {
+	SubmitGames(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[GameRecord, SubmitGamesResponse], error)
+	GetLatestModel(ctx context.Context, in *GetLatestModelRequest, opts ...grpc.CallOption) (*ModelInfo, error)
+	ReportMatchResult(ctx context.Context, in *MatchResult, opts ...grpc.CallOption) (*ReportMatchResultResponse, error)
+}
+
+type trainerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTrainerClient(cc grpc.ClientConnInterface) TrainerClient {
+	return &trainerClient{cc}
+}
+
+func (c *trainerClient) SubmitGames(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[GameRecord, SubmitGamesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Trainer_ServiceDesc.Streams[0], Trainer_SubmitGames_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GameRecord, SubmitGamesResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Trainer_SubmitGamesClient = grpc.ClientStreamingClient[GameRecord, SubmitGamesResponse]
+
+func (c *trainerClient) GetLatestModel(ctx context.Context, in *GetLatestModelRequest, opts ...grpc.CallOption) (*ModelInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ModelInfo)
+	err := c.cc.Invoke(ctx, Trainer_GetLatestModel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainerClient) ReportMatchResult(ctx context.Context, in *MatchResult, opts ...grpc.CallOption) (*ReportMatchResultResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportMatchResultResponse)
+	err := c.cc.Invoke(ctx, Trainer_ReportMatchResult_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TrainerServer is the server API for Trainer service.
+// All implementations must embed UnimplementedTrainerServer
+// for forward compatibility.
+//
+// The trainer receives the self-play games, trains the network and publishes its versions
+type TrainerServer interface {
+	SubmitGames(grpc.ClientStreamingServer[GameRecord, SubmitGamesResponse]) error
+	GetLatestModel(context.Context, *GetLatestModelRequest) (*ModelInfo, error)
+	ReportMatchResult(context.Context, *MatchResult) (*ReportMatchResultResponse, error)
+	mustEmbedUnimplementedTrainerServer()
+}
+
+// UnimplementedTrainerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTrainerServer struct{}
+
+func (UnimplementedTrainerServer) SubmitGames(grpc.ClientStreamingServer[GameRecord, SubmitGamesResponse]) error {
+	return status.Error(codes.Unimplemented, "method SubmitGames not implemented")
+}
+func (UnimplementedTrainerServer) GetLatestModel(context.Context, *GetLatestModelRequest) (*ModelInfo, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetLatestModel not implemented")
+}
+func (UnimplementedTrainerServer) ReportMatchResult(context.Context, *MatchResult) (*ReportMatchResultResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReportMatchResult not implemented")
+}
+func (UnimplementedTrainerServer) mustEmbedUnimplementedTrainerServer() {}
+func (UnimplementedTrainerServer) testEmbeddedByValue()                 {}
+
+// UnsafeTrainerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TrainerServer will
+// result in compilation errors.
+type UnsafeTrainerServer interface {
+	mustEmbedUnimplementedTrainerServer()
+}
+
+func RegisterTrainerServer(s grpc.ServiceRegistrar, srv TrainerServer) {
+	// If the following call panics, it indicates UnimplementedTrainerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Trainer_ServiceDesc, srv)
+}
+
+func _Trainer_SubmitGames_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TrainerServer).SubmitGames(&grpc.GenericServerStream[GameRecord, SubmitGamesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Trainer_SubmitGamesServer = grpc.ClientStreamingServer[GameRecord, SubmitGamesResponse]
+
+func _Trainer_GetLatestModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLatestModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainerServer).GetLatestModel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Trainer_GetLatestModel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainerServer).GetLatestModel(ctx, req.(*GetLatestModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Trainer_ReportMatchResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatchResult)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainerServer).ReportMatchResult(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Trainer_ReportMatchResult_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainerServer).ReportMatchResult(ctx, req.(*MatchResult))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Trainer_ServiceDesc is the grpc.ServiceDesc for Trainer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Trainer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "remote_trainer.Trainer",
+	HandlerType: (*TrainerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLatestModel",
+			Handler:    _Trainer_GetLatestModel_Handler,
+		},
+		{
+			MethodName: "ReportMatchResult",
+			Handler:    _Trainer_ReportMatchResult_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubmitGames",
+			Handler:       _Trainer_SubmitGames_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "proto/remote_trainer.proto",
+}
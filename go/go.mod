@@ -5,6 +5,8 @@ go 1.24.0
 require (
 	github.com/hajimehoshi/ebiten/v2 v2.9.8
 	golang.org/x/image v0.31.0
+	golang.org/x/net v0.48.0
+	golang.org/x/term v0.38.0
 	google.golang.org/grpc v1.79.1
 	google.golang.org/protobuf v1.36.10
 )
@@ -16,7 +18,6 @@ require (
 	github.com/go-text/typesetting v0.3.0 // indirect
 	github.com/jezek/xgb v1.1.1 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
-	golang.org/x/net v0.48.0 // indirect
 	golang.org/x/sync v0.19.0 // indirect
 	golang.org/x/sys v0.39.0 // indirect
 	golang.org/x/text v0.32.0 // indirect
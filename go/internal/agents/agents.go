@@ -7,3 +7,14 @@ import (
 type Agent interface {
 	SelectAction(go_ *environment.Game) environment.Action
 }
+
+// Agents that can keep searching while the opponent is thinking
+type Ponderer interface {
+	StartPondering(game *environment.Game)
+	StopPondering()
+}
+
+// Agents running goroutines between their searches, which must be stopped once the agent is no longer used
+type Closer interface {
+	Close()
+}
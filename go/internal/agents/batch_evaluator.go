@@ -0,0 +1,117 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
+)
+
+// Network inference is only efficient on batches: the batch evaluator collects the positions of all the
+// search routines and sends them in a single request once the batch is full or the oldest position waited long enough.
+type BatchEvaluator struct {
+	Client    remote_trainer.PositionEvaluatorClient
+	BatchSize int           // Maximum number of positions per request
+	Timeout   time.Duration // Maximum time a position waits for the batch to fill
+	Pending   chan PendingEvaluation
+	Done      chan bool
+	CloseOnce sync.Once
+}
+
+type PendingEvaluation struct {
+	Request *remote_trainer.EvaluatePositionRequest
+	Result  chan EvaluationResult
+}
+
+type EvaluationResult struct {
+	Response *remote_trainer.EvaluatePositionResponse
+	Err      error
+}
+
+// Constructor
+func NewBatchEvaluator(client remote_trainer.PositionEvaluatorClient, batch_size int, timeout time.Duration) *BatchEvaluator {
+	var evaluator *BatchEvaluator = &BatchEvaluator{
+		Client:    client,
+		BatchSize: max(1, batch_size),
+		Timeout:   timeout,
+		Pending:   make(chan PendingEvaluation), // Unbuffered so that no position is left behind when the evaluator is closed
+		Done:      make(chan bool),
+	}
+	go evaluator.Run()
+	return evaluator
+}
+
+// Methods
+func (evaluator *BatchEvaluator) Evaluate(request *remote_trainer.EvaluatePositionRequest) (*remote_trainer.EvaluatePositionResponse, error) {
+	// Blocks until the batch of the position has been evaluated
+	var result chan EvaluationResult = make(chan EvaluationResult, 1)
+	select {
+	case evaluator.Pending <- PendingEvaluation{Request: request, Result: result}:
+	case <-evaluator.Done:
+		return nil, errors.New("batch evaluator closed")
+	}
+	var evaluation EvaluationResult = <-result
+	return evaluation.Response, evaluation.Err
+}
+
+func (evaluator *BatchEvaluator) Run() {
+	var batch []PendingEvaluation = make([]PendingEvaluation, 0, evaluator.BatchSize)
+	var timer *time.Timer = time.NewTimer(evaluator.Timeout)
+	timer.Stop()
+	for {
+		select {
+		case pending := <-evaluator.Pending:
+			if len(batch) == 0 {
+				timer.Reset(evaluator.Timeout) // The timeout starts with the first position of the batch
+			}
+			batch = append(batch, pending)
+			if len(batch) < evaluator.BatchSize {
+				continue
+			}
+			timer.Stop()
+		case <-timer.C:
+			if len(batch) == 0 {
+				continue
+			}
+		case <-evaluator.Done:
+			timer.Stop()
+			evaluator.Fail(batch, errors.New("batch evaluator closed"))
+			return
+		}
+		// Keep collecting the next batch while this one is evaluated
+		go evaluator.Flush(batch)
+		batch = make([]PendingEvaluation, 0, evaluator.BatchSize)
+	}
+}
+
+func (evaluator *BatchEvaluator) Flush(batch []PendingEvaluation) {
+	var request *remote_trainer.EvaluatePositionBatchRequest = &remote_trainer.EvaluatePositionBatchRequest{
+		Positions: make([]*remote_trainer.EvaluatePositionRequest, len(batch)),
+	}
+	for position_idx, pending := range batch {
+		request.Positions[position_idx] = pending.Request
+	}
+	response, err := evaluator.Client.EvaluatePositionBatch(context.Background(), request)
+	if err == nil && len(response.Evaluations) != len(batch) {
+		err = errors.New("batch evaluation returned a wrong number of evaluations")
+	}
+	if err != nil {
+		evaluator.Fail(batch, err)
+		return
+	}
+	for position_idx, pending := range batch {
+		pending.Result <- EvaluationResult{Response: response.Evaluations[position_idx]}
+	}
+}
+
+func (evaluator *BatchEvaluator) Fail(batch []PendingEvaluation, err error) {
+	for _, pending := range batch {
+		pending.Result <- EvaluationResult{Err: err}
+	}
+}
+
+func (evaluator *BatchEvaluator) Close() {
+	evaluator.CloseOnce.Do(func() { close(evaluator.Done) })
+}
@@ -0,0 +1,250 @@
+package agents
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+)
+
+// A search budget decides how long SelectAction keeps searching
+type SearchBudget interface {
+	Start(agent *MctsAgent, game *environment.Game)
+	Remaining(agent *MctsAgent) int // Estimated number of simulations left, 0 or less once the budget is spent
+	Finish(agent *MctsAgent)
+}
+
+// Simulations budget: number of new simulations per move
+type SimulationsBudget struct {
+	Simulations int
+}
+
+func NewSimulationsBudget(simulations int) *SimulationsBudget {
+	return &SimulationsBudget{
+		Simulations: simulations,
+	}
+}
+
+func (budget *SimulationsBudget) Start(agent *MctsAgent, game *environment.Game) {}
+
+func (budget *SimulationsBudget) Remaining(agent *MctsAgent) int {
+	return budget.Simulations - agent.SimulationsDone.Get()
+}
+
+func (budget *SimulationsBudget) Finish(agent *MctsAgent) {}
+
+// Visits budget: number of visits of the root, visits reused from the previous moves included
+type VisitsBudget struct {
+	Visits int
+}
+
+func NewVisitsBudget(visits int) *VisitsBudget {
+	return &VisitsBudget{
+		Visits: visits,
+	}
+}
+
+func (budget *VisitsBudget) Start(agent *MctsAgent, game *environment.Game) {}
+
+func (budget *VisitsBudget) Remaining(agent *MctsAgent) int {
+	return budget.Visits - agent.Root.GetTotalN()
+}
+
+func (budget *VisitsBudget) Finish(agent *MctsAgent) {}
+
+// Nodes budget: size of the tree, nodes reused from the previous moves included
+type NodesBudget struct {
+	Nodes int
+}
+
+func NewNodesBudget(nodes int) *NodesBudget {
+	return &NodesBudget{
+		Nodes: nodes,
+	}
+}
+
+func (budget *NodesBudget) Start(agent *MctsAgent, game *environment.Game) {}
+
+func (budget *NodesBudget) Remaining(agent *MctsAgent) int {
+	return budget.Nodes - agent.NodeCount.Get()
+}
+
+func (budget *NodesBudget) Finish(agent *MctsAgent) {}
+
+// Time budget: fixed wall-clock time per move
+type TimeBudget struct {
+	Duration  time.Duration
+	StartTime time.Time
+}
+
+func NewTimeBudget(duration time.Duration) *TimeBudget {
+	return &TimeBudget{
+		Duration: duration,
+	}
+}
+
+func (budget *TimeBudget) Start(agent *MctsAgent, game *environment.Game) {
+	budget.StartTime = time.Now()
+}
+
+func (budget *TimeBudget) Remaining(agent *MctsAgent) int {
+	return EstimateRemainingSimulations(agent, budget.StartTime, budget.StartTime.Add(budget.Duration))
+}
+
+func (budget *TimeBudget) Finish(agent *MctsAgent) {}
+
+// Combination of budgets, the search stops as soon as one of them is spent
+type AnyBudget struct {
+	Budgets []SearchBudget
+}
+
+func NewAnyBudget(budgets ...SearchBudget) *AnyBudget {
+	return &AnyBudget{
+		Budgets: budgets,
+	}
+}
+
+func (budget *AnyBudget) Start(agent *MctsAgent, game *environment.Game) {
+	for _, sub_budget := range budget.Budgets {
+		sub_budget.Start(agent, game)
+	}
+}
+
+func (budget *AnyBudget) Remaining(agent *MctsAgent) int {
+	var remaining int = math.MaxInt32
+	for _, sub_budget := range budget.Budgets {
+		remaining = min(remaining, sub_budget.Remaining(agent))
+	}
+	return remaining
+}
+
+func (budget *AnyBudget) Finish(agent *MctsAgent) {
+	for _, sub_budget := range budget.Budgets {
+		sub_budget.Finish(agent)
+	}
+}
+
+// Time control budget: spends the time left on the clock over the expected rest of the game.
+// Each move gets a base share of the clock, extended up to MaxExtension times when the position
+// is unstable (the best move keeps changing or is closely followed) or critical (the game is balanced).
+type TimeControlBudget struct {
+	MainTime        time.Duration // Time left on the clock
+	Increment       time.Duration // Time added to the clock after each move
+	MovesLeft       int           // Expected number of moves left for the agent, estimated from the board when 0
+	MaxExtension    float64       // Maximum factor applied to the base time of a move
+	SafetyMargin    time.Duration // Time kept on the clock to avoid losing on time
+	Mutex           sync.Mutex
+	StartTime       time.Time
+	BaseDeadline    time.Time
+	MaxDeadline     time.Time
+	LastBestIdx     int
+	BestChanges     float64 // Decaying count of the changes of the most visited root action
+	NextStableCheck time.Time
+}
+
+func NewTimeControlBudget(main_time time.Duration, increment time.Duration) *TimeControlBudget {
+	return &TimeControlBudget{
+		MainTime:     main_time,
+		Increment:    increment,
+		MovesLeft:    0,
+		MaxExtension: 2.5,
+		SafetyMargin: 500 * time.Millisecond,
+	}
+}
+
+func (budget *TimeControlBudget) EstimateMovesLeft(game *environment.Game) int {
+	if budget.MovesLeft > 0 {
+		return budget.MovesLeft
+	}
+	// Each player fills about half of the empty points before the game ends
+	var empty_points int = 0
+	for i := 0; i < game.Board.Height; i++ {
+		for j := 0; j < game.Board.Width; j++ {
+			if game.Board.Matrix[i][j] == environment.Empty {
+				empty_points++
+			}
+		}
+	}
+	return max(10, empty_points/2)
+}
+
+func (budget *TimeControlBudget) Start(agent *MctsAgent, game *environment.Game) {
+	var available time.Duration = max(0, budget.MainTime-budget.SafetyMargin)
+	var base time.Duration = available/time.Duration(budget.EstimateMovesLeft(game)) + budget.Increment*4/5
+	var extended time.Duration = time.Duration(float64(base) * budget.MaxExtension)
+	budget.StartTime = time.Now()
+	budget.BaseDeadline = budget.StartTime.Add(min(base, available))
+	budget.MaxDeadline = budget.StartTime.Add(min(extended, available/4+budget.Increment))
+	budget.LastBestIdx = -1
+	budget.BestChanges = 0
+	budget.NextStableCheck = budget.StartTime
+}
+
+func (budget *TimeControlBudget) IsUnstable(agent *MctsAgent) bool {
+	var visits []int
+	var values []float64
+	visits, values = agent.Root.GetStatsSnapshot()
+	var best_idx, second_idx int = -1, -1
+	for action_idx := range visits {
+		if best_idx == -1 || visits[action_idx] > visits[best_idx] {
+			best_idx, second_idx = action_idx, best_idx
+		} else if second_idx == -1 || visits[action_idx] > visits[second_idx] {
+			second_idx = action_idx
+		}
+	}
+	if best_idx == -1 || second_idx == -1 {
+		return false // A single legal action, nothing to think about
+	}
+	budget.BestChanges *= 0.5
+	if budget.LastBestIdx != -1 && best_idx != budget.LastBestIdx {
+		budget.BestChanges += 1
+	}
+	budget.LastBestIdx = best_idx
+
+	var is_changing bool = budget.BestChanges > 0.2 // The best action changed during the last few checks
+	var is_close bool = float64(visits[second_idx]) > 0.6*float64(visits[best_idx])
+	var is_critical bool = math.Abs(values[best_idx]) < 0.2
+	return is_changing || is_close || is_critical
+}
+
+func (budget *TimeControlBudget) Remaining(agent *MctsAgent) int {
+	budget.Mutex.Lock()
+	defer budget.Mutex.Unlock()
+
+	var now time.Time = time.Now()
+	if now.After(budget.MaxDeadline) {
+		return 0
+	}
+	// Follow the stability of the root a few times per second, past the base time only keep going while it is unstable
+	if now.After(budget.NextStableCheck) {
+		budget.NextStableCheck = now.Add(50 * time.Millisecond)
+		var is_unstable bool = budget.IsUnstable(agent)
+		if !now.Before(budget.BaseDeadline) && !is_unstable {
+			budget.MaxDeadline = now
+			return 0
+		}
+	}
+	if now.Before(budget.BaseDeadline) {
+		return EstimateRemainingSimulations(agent, budget.StartTime, budget.BaseDeadline)
+	}
+	return EstimateRemainingSimulations(agent, budget.StartTime, budget.MaxDeadline)
+}
+
+func (budget *TimeControlBudget) Finish(agent *MctsAgent) {
+	budget.MainTime += budget.Increment - time.Since(budget.StartTime)
+}
+
+func EstimateRemainingSimulations(agent *MctsAgent, start_time time.Time, deadline time.Time) int {
+	var now time.Time = time.Now()
+	if !now.Before(deadline) {
+		return 0
+	}
+	var elapsed time.Duration = now.Sub(start_time)
+	var simulations_done int = agent.SimulationsDone.Get()
+	if simulations_done == 0 || elapsed <= 0 {
+		return math.MaxInt32 // No speed measured yet
+	}
+	var speed float64 = float64(simulations_done) / elapsed.Seconds()
+	return max(1, int(speed*deadline.Sub(now).Seconds()))
+}
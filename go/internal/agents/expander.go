@@ -6,6 +6,6 @@ import (
 )
 
 type Expander interface {
-	ExpandAndEvaluate(utils.Triple[MctsNode, int, *environment.Game]) int
+	ExpandAndEvaluate(utils.Triple[MctsNode, int, *environment.Game]) float64
 	GetToExpand() chan utils.Triple[MctsNode, int, *environment.Game]
 }
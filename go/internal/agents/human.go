@@ -0,0 +1,46 @@
+package agents
+
+import (
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/utils"
+)
+
+// HumanAgent plays the moves given by a user interface, SelectAction blocks until one arrives
+type HumanAgent struct {
+	Moves   chan environment.Action
+	Waiting *utils.LockedBool // Whether SelectAction is waiting for a move
+}
+
+// Constructor
+func NewHumanAgent() *HumanAgent {
+	return &HumanAgent{
+		Moves:   make(chan environment.Action, 1),
+		Waiting: utils.NewLockedBool(false),
+	}
+}
+
+// Methods
+func (agent *HumanAgent) SelectAction(game *environment.Game) environment.Action {
+	// Drop a move given while it was not our turn
+	select {
+	case <-agent.Moves:
+	default:
+	}
+	agent.Waiting.Set(true)
+	var action environment.Action = <-agent.Moves
+	agent.Waiting.Set(false)
+	return action
+}
+
+func (agent *HumanAgent) Play(action environment.Action) bool {
+	// Returns false if the agent is not waiting for a move or already has one
+	if !agent.Waiting.Get() {
+		return false
+	}
+	select {
+	case agent.Moves <- action:
+		return true
+	default:
+		return false
+	}
+}
@@ -1,8 +1,10 @@
 package agents
 
 import (
+	"math/rand"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
 	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/utils"
@@ -11,33 +13,64 @@ import (
 type MctsAgent struct {
 	SimulationsPerMove int
 	SimulationsDone    *utils.LockedValue
+	Budget             SearchBudget
+	EarlyStopping      bool               // Stop as soon as the most visited root action cannot be overtaken
+	NextEarlyStopCheck *utils.LockedValue // Number of simulations after which early stopping is checked again
+	NodeCount          *utils.LockedValue // Number of nodes in the tree, root included
 	NbRoutines         int
 	Root               MctsNode
-	ToBackpropagate    chan utils.Triple[MctsNode, int, *environment.Game]
+	RootGame           *environment.Game // Position of the root, used to find it again after the next moves are played
+	ToBackpropagate    chan utils.Triple[MctsNode, float64, *environment.Game]
 	ResignThreshold    float64
 	Expander           Expander
+	PonderRoutines     int // Number of routines searching during the opponent's turn (0 disables pondering)
+	PonderMaxNodes     int // Maximum size of the tree while pondering (0 means no limit)
+	PonderMutex        sync.Mutex
+	PonderStop         *utils.LockedBool
+	PonderDone         chan bool
+	SelfPlay           *SelfPlayConfig // nil to always play the most visited action without root noise
+	NoisyRoot          MctsNode        // Root which already received the Dirichlet noise
+	Rng                *rand.Rand
+	SearchStart        time.Time
+	InfoCallback       func(info SearchInfo) // Receives snapshots of the search while it is running and its final result, nil to disable
+	InfoInterval       time.Duration
+	InfoCandidates     int // Number of candidate moves in the search info
+	InfoPVLength       int // Maximum length of the principal variations in the search info
+	LastSearchInfo     SearchInfo
 }
 
 // Constructor
 func NewMctsAgent(simulations_per_move int, nb_routines int, resign_threshold float64, expander Expander) *MctsAgent {
 	return &MctsAgent{
 		SimulationsPerMove: simulations_per_move,
+		SimulationsDone:    utils.NewLockedValue(0),
+		Budget:             NewSimulationsBudget(simulations_per_move),
+		EarlyStopping:      false,
+		NextEarlyStopCheck: utils.NewLockedValue(0),
+		NodeCount:          utils.NewLockedValue(0),
 		NbRoutines:         nb_routines,
-		ToBackpropagate:    make(chan utils.Triple[MctsNode, int, *environment.Game], nb_routines),
+		ToBackpropagate:    make(chan utils.Triple[MctsNode, float64, *environment.Game], nb_routines),
 		ResignThreshold:    resign_threshold,
 		Expander:           expander,
+		InfoCallback:       nil,
+		InfoInterval:       200 * time.Millisecond,
+		InfoCandidates:     10,
+		InfoPVLength:       10,
 	}
 }
 
 // Methods
 func (agent *MctsAgent) GetFinalAction(legal_actions []environment.Action) environment.Action {
 	// get the argmax of self.root.N
+	var root_n []int
+	var root_q []float64
+	root_n, root_q = agent.Root.GetStatsSnapshot()
 	var best_action_index int = 0
-	var max_visits int = agent.Root.GetN()[0]
-	var max_value float64 = agent.Root.GetQ()[0]
-	for action_index, visits := range agent.Root.GetN() {
-		if agent.Root.GetQ()[action_index] > max_value {
-			max_value = agent.Root.GetQ()[action_index]
+	var max_visits int = root_n[0]
+	var max_value float64 = root_q[0]
+	for action_index, visits := range root_n {
+		if root_q[action_index] > max_value {
+			max_value = root_q[action_index]
 		}
 		if visits > max_visits {
 			max_visits = visits
@@ -49,34 +82,64 @@ func (agent *MctsAgent) GetFinalAction(legal_actions []environment.Action) envir
 		return environment.Resign{}
 	}
 
+	if agent.UsesTemperature() {
+		return legal_actions[agent.SampleActionIndex(root_n, legal_actions)]
+	}
+
 	return legal_actions[best_action_index]
 }
 
 func (agent *MctsAgent) SelectLeaf(node MctsNode, game *environment.Game) utils.Triple[MctsNode, int, *environment.Game] {
-	var best_action_idx int = node.SelectBestChildIndex()
 	if game.IsTerminal() {
 		return utils.NewTriple(node, -1, game.DeepCopy())
 	}
-	if node.GetChildren()[best_action_idx] == nil {
+	var best_action_idx int = node.SelectBestChildIndex()
+	var child MctsNode = node.GetChild(best_action_idx)
+	if child == nil {
 		return utils.NewTriple(node, best_action_idx, game.DeepCopy())
 	}
 	game.PlayAction(game.LegalActions[best_action_idx])
-	return agent.SelectLeaf(node.GetChildren()[best_action_idx], game)
+	return agent.SelectLeaf(child, game)
 }
 
-func (agent *MctsAgent) Backpropagate(to_backpropagate utils.Triple[MctsNode, int, *environment.Game]) {
+func (agent *MctsAgent) Backpropagate(to_backpropagate utils.Triple[MctsNode, float64, *environment.Game]) {
 	var node MctsNode = to_backpropagate.First
-	var value int = to_backpropagate.Second
+	var value float64 = to_backpropagate.Second
+	var final_game *environment.Game = to_backpropagate.Third // Game at the end of the simulation, nil if unknown
 	if node.GetParent() != nil {
 		node.GetParent().UpdateStats(value, node.GetIdx())
-		agent.Backpropagate(utils.NewTriple(node.GetParent(), -value, (*environment.Game)(nil)))
+		if final_game != nil {
+			node.GetParent().UpdateAmafStats(value, final_game.MoveHistory)
+		}
+		agent.Backpropagate(utils.NewTriple(node.GetParent(), -value, final_game))
 	}
 }
 
-func (agent *MctsAgent) ExploreTree(wg *sync.WaitGroup, game *environment.Game) {
+func (agent *MctsAgent) RevertVirtualLoss(node MctsNode, action_idx int) {
+	// Undo the virtual losses added along the path when the selected leaf is dropped
+	node.RevertVirtualLoss(action_idx)
+	if node.GetParent() != nil {
+		agent.RevertVirtualLoss(node.GetParent(), node.GetIdx())
+	}
+}
+
+func (agent *MctsAgent) TerminalValue(game *environment.Game) float64 {
+	// Value of the game for the parent of the terminal node
+	var winner environment.Stone = game.GetWinner()
+	switch winner {
+	case environment.Empty:
+		return 0 // Draw
+	case game.Board.CurrentPlayer:
+		return -1 // Loss for the parent
+	default:
+		return 1 // Win for the parent
+	}
+}
+
+func (agent *MctsAgent) ExploreTree(wg *sync.WaitGroup, game *environment.Game, keep_searching func() bool) {
 
 	defer wg.Done()
-	for agent.SimulationsDone.Get() < agent.SimulationsPerMove {
+	for keep_searching() {
 		select {
 		case to_backpropagate := <-agent.ToBackpropagate:
 			agent.Backpropagate(to_backpropagate)
@@ -84,26 +147,20 @@ func (agent *MctsAgent) ExploreTree(wg *sync.WaitGroup, game *environment.Game)
 			if to_expand.Second == -1 {
 				// Terminal node reached, no expansion
 				agent.SimulationsDone.Incr() // We are sure to expand a new node
-				var value int                // Value of the game for the parent of the backpropagated node (terminal node)
-				var winner environment.Stone = to_expand.Third.GetWinner()
-				switch winner {
-				case environment.Empty:
-					value = 0 // Draw
-				case to_expand.Third.Board.CurrentPlayer:
-					value = -1 // Loss for the parent
-				default:
-					value = 1 // Win for the parent
-				}
-
-				agent.ToBackpropagate <- utils.NewTriple(to_expand.First, value, (*environment.Game)(nil))
+				var value float64 = agent.TerminalValue(to_expand.Third)
+				agent.ToBackpropagate <- utils.NewTriple(to_expand.First, value, to_expand.Third)
 				continue
 			}
 			// atomic check to avoid expanding the same node multiple times
 			if atomic.CompareAndSwapInt32((&to_expand.First.GetIsExpanded()[to_expand.Second]), 0, 1) {
-				agent.SimulationsDone.Incr()                                // We are sure to expand a new node
-				var value int = agent.Expander.ExpandAndEvaluate(to_expand) // Value of the game for the parent of the backpropagated node (expanded node)
-				var expanded_child MctsNode = to_expand.First.GetChildren()[to_expand.Second]
-				agent.ToBackpropagate <- utils.NewTriple(expanded_child, value, (*environment.Game)(nil))
+				agent.SimulationsDone.Incr() // We are sure to expand a new node
+				agent.NodeCount.Incr()
+				var value float64 = agent.Expander.ExpandAndEvaluate(to_expand) // Value of the game for the parent of the backpropagated node (expanded node)
+				var expanded_child MctsNode = to_expand.First.GetChild(to_expand.Second)
+				agent.ToBackpropagate <- utils.NewTriple(expanded_child, value, to_expand.Third) // The expander played the simulation to the end on this game
+			} else {
+				// Another routine is expanding this child, drop the leaf
+				agent.RevertVirtualLoss(to_expand.First, to_expand.Second)
 			}
 
 		default:
@@ -114,28 +171,203 @@ func (agent *MctsAgent) ExploreTree(wg *sync.WaitGroup, game *environment.Game)
 	}
 }
 
-func (agent *MctsAgent) SelectAction(game *environment.Game) environment.Action {
+func (agent *MctsAgent) DrainPending() {
+	// Leaves still queued when the routines stop would leave virtual losses in the tree, which is kept for the next move
+	for {
+		select {
+		case to_backpropagate := <-agent.ToBackpropagate:
+			agent.Backpropagate(to_backpropagate)
+		case to_expand := <-agent.Expander.GetToExpand():
+			if to_expand.Second == -1 {
+				agent.Backpropagate(utils.NewTriple(to_expand.First, agent.TerminalValue(to_expand.Third), to_expand.Third))
+			} else {
+				agent.RevertVirtualLoss(to_expand.First, to_expand.Second)
+			}
+		default:
+			return
+		}
+	}
+}
 
-	// reset MCTS tree
+func (agent *MctsAgent) NewRoot(game *environment.Game) MctsNode {
 	switch expander := agent.Expander.(type) {
 	case *UctExpander:
-		agent.Root = NewUctNode(game, nil, -1)
+		var root *UctNode = NewUctNode(game, nil, -1)
+		root.Rave = expander.Rave
+		return root
 	case *PuctExpander:
-		agent.Root = NewPuctNode(game, nil, -1, expander.Client)
+		return expander.NewRoot(game)
 	default:
 		panic("Unknown expander type")
 	}
+}
 
+func (agent *MctsAgent) ResetTree() {
+	agent.Root = nil
+	agent.RootGame = nil
+}
+
+func (agent *MctsAgent) CountNodes(node MctsNode) int {
+	var count int = 1
+	for _, child := range node.GetChildren() {
+		if child != nil {
+			count += agent.CountNodes(child)
+		}
+	}
+	return count
+}
+
+func (agent *MctsAgent) SetRoot(game *environment.Game) {
+	// reuse the MCTS tree of the previous search if it still matches the position, reset it otherwise
+	if agent.ReuseTree(game) {
+		agent.NodeCount.Set(agent.CountNodes(agent.Root))
+	} else {
+		agent.Root = agent.NewRoot(game)
+		agent.RootGame = game.DeepCopy()
+		agent.NodeCount.Set(1)
+	}
+	// Forget the transpositions that are not reachable anymore
+	if table := agent.GetTranspositionTable(); table != nil {
+		table.Rebuild(agent.Root)
+	}
+}
+
+func (agent *MctsAgent) ReuseTree(game *environment.Game) bool {
+	// Promote the subtree of the moves played since the last search to the new root.
+	// Returns false if the tree does not lead to the given position, in which case it must be rebuilt.
+	if agent.Root == nil || agent.RootGame == nil {
+		return false
+	}
+	var root_history []uint64 = agent.RootGame.BoardHasher.HashHistory
+	var game_history []uint64 = game.BoardHasher.HashHistory
+	if game.Komi != agent.RootGame.Komi || game.Board.Height != agent.RootGame.Board.Height || game.Board.Width != agent.RootGame.Board.Width {
+		return false
+	}
+	if game.BoardHasher.PlayerHash != agent.RootGame.BoardHasher.PlayerHash || len(game_history) < len(root_history) {
+		return false // Not the same game (hashes are only comparable with the same zobrist table)
+	}
+	for move, hash := range root_history {
+		if game_history[move] != hash {
+			return false
+		}
+	}
+
+	// Follow the moves played since the root, each one is identified by the hash of the position it leads to
+	var node MctsNode = agent.Root
+	var node_game *environment.Game = agent.RootGame
+	for move := len(root_history); move < len(game_history); move++ {
+		var next_node MctsNode = nil
+		var next_game *environment.Game = nil
+		for action_idx, child := range node.GetChildren() {
+			if child == nil {
+				continue
+			}
+			if _, is_resign := node_game.LegalActions[action_idx].(environment.Resign); is_resign {
+				continue // Resigning only switches the player, like passing
+			}
+			var child_game *environment.Game = node_game.DeepCopy()
+			child_game.PlayAction(node_game.LegalActions[action_idx])
+			if child_game.BoardHasher.BoardHash == game_history[move] {
+				next_node = child
+				next_game = child_game
+				break
+			}
+		}
+		if next_node == nil {
+			return false // The move played was never explored
+		}
+		node = next_node
+		node_game = next_game
+	}
+
+	if node_game.Board.Passes != game.Board.Passes || len(node_game.LegalActions) != len(game.LegalActions) {
+		return false
+	}
+
+	node.SetParent(nil) // Detach the subtree so that backpropagation stops at the new root
+	agent.Root = node
+	agent.RootGame = game.DeepCopy()
+	return true
+}
+
+func (agent *MctsAgent) CanStopEarly(remaining int) bool {
+	// Looking at the root is costly with many routines, only do it every few simulations
+	var simulations_done int = agent.SimulationsDone.Get()
+	if simulations_done < agent.NextEarlyStopCheck.Get() {
+		return false
+	}
+	agent.NextEarlyStopCheck.Set(simulations_done + 16)
+
+	var visits []int
+	visits, _ = agent.Root.GetStatsSnapshot()
+	var best_visits, second_visits int = 0, 0
+	for _, action_visits := range visits {
+		if action_visits > best_visits {
+			best_visits, second_visits = action_visits, best_visits
+		} else if action_visits > second_visits {
+			second_visits = action_visits
+		}
+	}
+	return best_visits-second_visits > remaining
+}
+
+func (agent *MctsAgent) KeepSearching() bool {
+	var remaining int = agent.Budget.Remaining(agent)
+	if remaining <= 0 {
+		return false
+	}
+	return !(agent.EarlyStopping && agent.CanStopEarly(remaining))
+}
+
+func (agent *MctsAgent) Search(game *environment.Game, nb_routines int, keep_searching func() bool) {
 	var wg sync.WaitGroup
-	wg.Add(agent.NbRoutines)
+	wg.Add(nb_routines)
 
-	for nb_routines := 0; nb_routines < agent.NbRoutines; nb_routines++ {
+	for routine := 0; routine < nb_routines; routine++ {
 
-		go agent.ExploreTree(&wg, game)
+		go agent.ExploreTree(&wg, game, keep_searching)
 	}
 
 	wg.Wait()
+	agent.DrainPending()
+}
+
+func (agent *MctsAgent) SelectAction(game *environment.Game) environment.Action {
+
+	// The opponent has played, stop searching the position we predicted for them
+	agent.StopPondering()
+
+	agent.SetRoot(game)
+	agent.AddRootNoise()
+	agent.SimulationsDone.Set(0)
+	agent.NextEarlyStopCheck.Set(0)
+	agent.SearchStart = time.Now()
+	var info_done, info_stopped chan bool = make(chan bool), make(chan bool)
+	if agent.InfoCallback != nil {
+		go agent.PublishSearchInfo(info_done, info_stopped)
+	} else {
+		close(info_stopped)
+	}
+
+	agent.Budget.Start(agent, game)
+	agent.Search(game, agent.NbRoutines, agent.KeepSearching)
+	agent.Budget.Finish(agent)
+
+	close(info_done)
+	<-info_stopped
+	agent.LastSearchInfo = agent.GetSearchInfo(agent.InfoCandidates, agent.InfoPVLength)
+	if agent.InfoCallback != nil {
+		agent.InfoCallback(agent.LastSearchInfo)
+	}
 
 	var final_action environment.Action = agent.GetFinalAction(game.LegalActions)
 	return final_action
 }
+
+func (agent *MctsAgent) Close() {
+	// Stops pondering and the goroutines of the expander, the agent cannot search anymore
+	agent.StopPondering()
+	if closer, ok := agent.Expander.(Closer); ok {
+		closer.Close()
+	}
+}
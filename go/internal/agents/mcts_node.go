@@ -5,11 +5,19 @@ import "github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
 type MctsNode interface {
 	Reset(game *environment.Game)
 	SelectBestChildIndex() int
-	UpdateStats(value int, action_idx int)
+	UpdateStats(value float64, action_idx int)
+	UpdateAmafStats(value float64, move_history []environment.Action)
+	RevertVirtualLoss(action_idx int)
 	GetParent() MctsNode
+	SetParent(parent MctsNode)
 	GetIdx() int
+	GetTotalN() int
 	GetN() []int
 	GetQ() []float64
+	GetStatsSnapshot() ([]int, []float64)
+	GetValue() float64
+	GetChild(action_idx int) MctsNode
+	SetChild(action_idx int, child MctsNode)
 	GetChildren() []MctsNode
 	GetIsExpanded() []int32
 }
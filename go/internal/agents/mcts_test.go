@@ -0,0 +1,29 @@
+package agents
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+)
+
+// Search speed against the number of routines, run with: go test ./internal/agents -run '^$' -bench SearchRoutines
+
+const BenchmarkSimulations int = 500
+
+func BenchmarkSearchRoutines(b *testing.B) {
+	var game *environment.Game = environment.NewGame(9, 9, 6.5)
+	for _, routines := range ScalingRoutines(MaxScalingRoutines) {
+		b.Run(fmt.Sprintf("routines=%d", routines), func(b *testing.B) {
+			var total_simulations int = 0
+			var total_time time.Duration = 0
+			for b.Loop() {
+				simulations, elapsed := TimeSearch(BenchmarkSimulations, routines, game)
+				total_simulations += simulations
+				total_time += elapsed
+			}
+			b.ReportMetric(float64(total_simulations)/total_time.Seconds(), "simulations/s")
+		})
+	}
+}
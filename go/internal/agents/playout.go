@@ -0,0 +1,333 @@
+package agents
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+)
+
+// Playout policies play both sides of the simulations of the UCT expander, they are agents so that any agent can be used
+
+func NewPlayoutPolicy(name string) (Agent, error) {
+	switch name {
+	case "random":
+		return NewRandomAgent(), nil
+	case "pattern":
+		return NewPatternPolicy(), nil
+	default:
+		return nil, fmt.Errorf("unknown playout policy %q, expected random or pattern", name)
+	}
+}
+
+func (agent *MctsAgent) SetPlayoutPolicy(policy Agent) {
+	switch expander := agent.Expander.(type) {
+	case *UctExpander:
+		expander.Playout = policy
+	case *PuctExpander:
+		expander.Playout = policy // Unused, the network evaluates the positions
+	default:
+		panic("Unknown expander type")
+	}
+}
+
+// 3x3 patterns centered on the candidate move, from the MoGo playouts (Gelly et al., 2006).
+// X and O are the two colors (both assignments are tried), x is anything but X, o anything but O,
+// . is empty, a space is off the board and ? matches anything. The patterns also match in every orientation.
+type PlayoutPattern struct {
+	Name   string
+	Rows   [3]string
+	Weight float64
+}
+
+var PlayoutPatterns []PlayoutPattern = []PlayoutPattern{
+	{"enclosing hane", [3]string{"XOX", "...", "???"}, 1.0},
+	{"non-cutting hane", [3]string{"XO.", "...", "?.?"}, 1.0},
+	{"magari", [3]string{"XO?", "X..", "x.?"}, 1.0},
+	{"katatsuke", [3]string{".O.", "X..", "..."}, 0.8},
+	{"unprotected cut", [3]string{"XO?", "O.o", "?o?"}, 1.2},
+	{"peeping cut", [3]string{"XO?", "O.X", "???"}, 1.2},
+	{"de", [3]string{"?X?", "O.O", "ooo"}, 1.2},
+	{"keima cut", [3]string{"OX?", "o.O", "???"}, 1.0},
+	{"side chase", [3]string{"X.?", "O.?", "   "}, 0.8},
+	{"side block cut", [3]string{"OX?", "X.O", "   "}, 0.8},
+	{"side block connection", [3]string{"?X?", "x.O", "   "}, 0.8},
+	{"sagari", [3]string{"?XO", "x.x", "   "}, 0.8},
+	{"side cut", [3]string{"?OX", "X.O", "   "}, 0.8},
+}
+
+const OffBoard environment.Stone = 3
+
+// Weight of every 3x3 neighborhood, indexed by NeighborhoodCode
+var PatternWeights []float64 = BuildPatternWeights(PlayoutPatterns)
+
+func BuildPatternWeights(patterns []PlayoutPattern) []float64 {
+	var weights []float64 = make([]float64, 1<<16)
+	for _, pattern := range patterns {
+		for symmetry := 0; symmetry < 8; symmetry++ {
+			// Cell (i, j) of the oriented pattern, the same transforms as the feature planes
+			var cells []byte = make([]byte, 0, 8)
+			for i := 0; i < 3; i++ {
+				for j := 0; j < 3; j++ {
+					if i == 1 && j == 1 {
+						continue
+					}
+					var si, sj int = i, j
+					if symmetry&4 != 0 {
+						si, sj = sj, si
+					}
+					if symmetry&1 != 0 {
+						si = 2 - si
+					}
+					if symmetry&2 != 0 {
+						sj = 2 - sj
+					}
+					cells = append(cells, pattern.Rows[si][sj])
+				}
+			}
+			for _, x_color := range []environment.Stone{environment.Black, environment.White} {
+				ExpandPattern(weights, cells, x_color, 0, 0, pattern.Weight)
+			}
+		}
+	}
+	return weights
+}
+
+func ExpandPattern(weights []float64, cells []byte, x_color environment.Stone, cell int, code int, weight float64) {
+	if cell == len(cells) {
+		weights[code] = max(weights[code], weight)
+		return
+	}
+	var o_color environment.Stone = x_color.Opponent()
+	for _, value := range []environment.Stone{environment.Empty, environment.Black, environment.White, OffBoard} {
+		var matches bool
+		switch cells[cell] {
+		case 'X':
+			matches = value == x_color
+		case 'O':
+			matches = value == o_color
+		case 'x':
+			matches = value == o_color || value == environment.Empty
+		case 'o':
+			matches = value == x_color || value == environment.Empty
+		case '.':
+			matches = value == environment.Empty
+		case ' ':
+			matches = value == OffBoard
+		case '?':
+			matches = true
+		}
+		if matches {
+			ExpandPattern(weights, cells, x_color, cell+1, code|int(value)<<(2*cell), weight)
+		}
+	}
+}
+
+func NeighborhoodCode(board *environment.Board, i, j int) int {
+	var code int = 0
+	var cell int = 0
+	for di := -1; di <= 1; di++ {
+		for dj := -1; dj <= 1; dj++ {
+			if di == 0 && dj == 0 {
+				continue
+			}
+			var value environment.Stone = OffBoard
+			if ni, nj := i+di, j+dj; ni >= 0 && ni < board.Height && nj >= 0 && nj < board.Width {
+				value = board.Matrix[ni][nj]
+			}
+			code |= int(value) << (2 * cell)
+			cell++
+		}
+	}
+	return code
+}
+
+func IsTrueEye(board *environment.Board, i, j int, player environment.Stone) bool {
+	// Empty point surrounded by the player, with at most one opponent diagonal (none on the edge)
+	if board.Matrix[i][j] != environment.Empty {
+		return false
+	}
+	for _, stone := range board.GetNeighbors(i, j) {
+		if stone != player {
+			return false
+		}
+	}
+	var off_board, opponent int = 0, 0
+	for _, di := range []int{-1, 1} {
+		for _, dj := range []int{-1, 1} {
+			var ni, nj int = i + di, j + dj
+			switch {
+			case ni < 0 || ni >= board.Height || nj < 0 || nj >= board.Width:
+				off_board++
+			case board.Matrix[ni][nj] == player.Opponent():
+				opponent++
+			}
+		}
+	}
+	if off_board > 0 {
+		return opponent == 0
+	}
+	return opponent < 2
+}
+
+func GroupLiberties(board *environment.Board, i, j int) (map[environment.Position]bool, []environment.Position) {
+	// Liberties and stones of the group at (i, j), found with a flood fill
+	var stone environment.Stone = board.Matrix[i][j]
+	var stones []environment.Position = []environment.Position{environment.NewPosition(i, j)}
+	var visited map[environment.Position]bool = map[environment.Position]bool{stones[0]: true}
+	var liberties map[environment.Position]bool = make(map[environment.Position]bool)
+	for next := 0; next < len(stones); next++ {
+		for neighbor, neighbor_stone := range board.GetNeighbors(stones[next].First, stones[next].Second) {
+			switch {
+			case neighbor_stone == environment.Empty:
+				liberties[neighbor] = true
+			case neighbor_stone == stone && !visited[neighbor]:
+				visited[neighbor] = true
+				stones = append(stones, neighbor)
+			}
+		}
+	}
+	return liberties, stones
+}
+
+func LibertiesAfter(board *environment.Board, point environment.Position, player environment.Stone) int {
+	// Liberties of the group formed by playing at the point, captures aside
+	var liberties map[environment.Position]bool = make(map[environment.Position]bool)
+	for neighbor, stone := range board.GetNeighbors(point.First, point.Second) {
+		switch stone {
+		case environment.Empty:
+			liberties[neighbor] = true
+		case player:
+			group_liberties, _ := GroupLiberties(board, neighbor.First, neighbor.Second)
+			for liberty := range group_liberties {
+				liberties[liberty] = true
+			}
+		}
+	}
+	delete(liberties, point)
+	return len(liberties)
+}
+
+// Pattern policy: answers the last move by capturing, saving groups in atari and playing the 3x3 patterns
+// around it, otherwise plays a uniform move. It never fills its own true eyes and only passes when nothing else is left.
+type PatternPolicy struct {
+	CaptureWeight float64 // Per stone of the captured group
+	SaveWeight    float64 // Per stone of the group saved from atari
+	PatternScale  float64 // Multiplies the weights of the patterns
+	NearWeight    float64 // Empty points around the last move matching no pattern
+	RandomWeight  float64 // Uniform move anywhere on the board, against the sum of the local weights
+}
+
+func NewPatternPolicy() *PatternPolicy {
+	return &PatternPolicy{
+		CaptureWeight: 10,
+		SaveWeight:    8,
+		PatternScale:  4,
+		NearWeight:    0.25,
+		RandomWeight:  2,
+	}
+}
+
+func (policy *PatternPolicy) LocalWeights(game *environment.Game) map[environment.Position]float64 {
+	var weights map[environment.Position]float64 = make(map[environment.Position]float64)
+	if len(game.MoveHistory) == 0 {
+		return weights
+	}
+	last_move, ok := game.MoveHistory[len(game.MoveHistory)-1].(environment.PutStone)
+	if !ok {
+		return weights
+	}
+	var board *environment.Board = game.Board
+	var player environment.Stone = board.CurrentPlayer
+
+	// Groups in atari around the last move
+	var seen map[environment.Position]bool = make(map[environment.Position]bool)
+	for i := max(0, last_move.I-1); i <= min(board.Height-1, last_move.I+1); i++ {
+		for j := max(0, last_move.J-1); j <= min(board.Width-1, last_move.J+1); j++ {
+			var stone environment.Stone = board.Matrix[i][j]
+			if stone == environment.Empty {
+				continue
+			}
+			var root environment.Position = board.UnionFind.Find(environment.NewPosition(i, j))
+			if seen[root] {
+				continue
+			}
+			seen[root] = true
+			liberties, stones := GroupLiberties(board, i, j)
+			if len(liberties) != 1 {
+				continue
+			}
+			var liberty environment.Position
+			for liberty = range liberties {
+			}
+			if stone != player {
+				weights[liberty] += policy.CaptureWeight * float64(len(stones))
+				continue
+			}
+			// Our group is in atari: extend if that gives it room, or capture one of the attackers
+			if LibertiesAfter(board, liberty, player) >= 2 {
+				weights[liberty] += policy.SaveWeight * float64(len(stones))
+			}
+			for _, own_stone := range stones {
+				for neighbor, neighbor_stone := range board.GetNeighbors(own_stone.First, own_stone.Second) {
+					if neighbor_stone != player.Opponent() {
+						continue
+					}
+					attacker_liberties, _ := GroupLiberties(board, neighbor.First, neighbor.Second)
+					if len(attacker_liberties) == 1 {
+						for attacker_liberty := range attacker_liberties {
+							weights[attacker_liberty] += policy.SaveWeight * float64(len(stones))
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Shape around the last move
+	for i := max(0, last_move.I-1); i <= min(board.Height-1, last_move.I+1); i++ {
+		for j := max(0, last_move.J-1); j <= min(board.Width-1, last_move.J+1); j++ {
+			if board.Matrix[i][j] != environment.Empty {
+				continue
+			}
+			var weight float64 = policy.PatternScale * PatternWeights[NeighborhoodCode(board, i, j)]
+			if weight == 0 {
+				weight = policy.NearWeight
+			}
+			weights[environment.NewPosition(i, j)] += weight
+		}
+	}
+
+	for point := range weights {
+		if IsTrueEye(board, point.First, point.Second, player) || !game.IsLegalAction(point.First, point.Second) {
+			delete(weights, point)
+		}
+	}
+	return weights
+}
+
+func (policy *PatternPolicy) SelectAction(game *environment.Game) environment.Action {
+	var weights map[environment.Position]float64 = policy.LocalWeights(game)
+	var total float64 = 0
+	for _, weight := range weights {
+		total += weight
+	}
+	var r float64 = rand.Float64() * (total + policy.RandomWeight)
+	for point, weight := range weights {
+		r -= weight
+		if r < 0 {
+			return environment.PutStone{I: point.First, J: point.Second}
+		}
+	}
+
+	// Uniform move, scanning the legal stones from a random start to skip the eyes
+	var stones []environment.Action = game.LegalActions[2:] // Skip resign and pass
+	var start int = rand.Intn(max(1, len(stones)))
+	for offset := range stones {
+		var action environment.PutStone = stones[(start+offset)%len(stones)].(environment.PutStone)
+		if !IsTrueEye(game.Board, action.I, action.J, game.Board.CurrentPlayer) {
+			return action
+		}
+	}
+	return environment.Pass{}
+}
@@ -0,0 +1,55 @@
+package agents
+
+import (
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/utils"
+)
+
+func (agent *MctsAgent) SetPondering(ponder_routines int, ponder_max_nodes int) {
+	agent.StopPondering()
+	agent.PonderRoutines = min(ponder_routines, agent.NbRoutines) // The channels of the agent are sized for NbRoutines routines
+	agent.PonderMaxNodes = ponder_max_nodes
+}
+
+// Search the position the opponent has to play in until StopPondering is called or the tree reaches PonderMaxNodes nodes.
+// The tree is kept, so the subtree of the move the opponent actually plays is reused by the next SelectAction.
+func (agent *MctsAgent) StartPondering(game *environment.Game) {
+	agent.StopPondering()
+
+	agent.PonderMutex.Lock()
+	defer agent.PonderMutex.Unlock()
+
+	if agent.PonderRoutines <= 0 || game.IsTerminal() {
+		return
+	}
+
+	var ponder_game *environment.Game = game.DeepCopy()
+	var ponder_stop *utils.LockedBool = utils.NewLockedBool(false)
+	var ponder_done chan bool = make(chan bool)
+	agent.SetRoot(ponder_game)
+	agent.PonderStop = ponder_stop
+	agent.PonderDone = ponder_done
+
+	go func() {
+		defer close(ponder_done)
+		agent.Search(ponder_game, agent.PonderRoutines, func() bool {
+			if ponder_stop.Get() {
+				return false
+			}
+			return agent.PonderMaxNodes <= 0 || agent.NodeCount.Get() < agent.PonderMaxNodes
+		})
+	}()
+}
+
+func (agent *MctsAgent) StopPondering() {
+	agent.PonderMutex.Lock()
+	defer agent.PonderMutex.Unlock()
+
+	if agent.PonderDone == nil {
+		return // Not pondering
+	}
+	agent.PonderStop.Set(true)
+	<-agent.PonderDone // Wait for the routines to leave the tree in a consistent state
+	agent.PonderStop = nil
+	agent.PonderDone = nil
+}
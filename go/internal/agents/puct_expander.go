@@ -1,10 +1,12 @@
 package agents
 
 import (
-	"context"
+	"math/rand"
+	"time"
 
 	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
 	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/features"
 	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/utils"
 )
 
@@ -12,59 +14,108 @@ import (
 
 type PuctExpander struct {
 	*UctExpander
-	Client remote_trainer.PositionEvaluatorClient
+	Client         remote_trainer.PositionEvaluatorClient
+	Evaluator      *BatchEvaluator
+	RandomSymmetry bool                         // Evaluate each position under a random symmetry of the board
+	ModelVersion   *utils.LockedPointer[string] // Version of the network asked to the evaluator, can be swapped during a search
 }
 
 func NewPuctExpander(nb_routines int, client remote_trainer.PositionEvaluatorClient) *PuctExpander {
 	var UctExpander *UctExpander = NewUctExpander(nb_routines)
 	return &PuctExpander{
-		UctExpander: UctExpander,
-		Client:      client,
+		UctExpander:    UctExpander,
+		Client:         client,
+		Evaluator:      NewBatchEvaluator(client, nb_routines, 2*time.Millisecond), // At most one leaf per routine is waiting for its evaluation
+		RandomSymmetry: true,
+		ModelVersion:   utils.NewLockedPointer(new(string)),
 	}
 }
 
+func (expander *PuctExpander) SetBatching(batch_size int, timeout time.Duration) {
+	// Must not be called while searching
+	expander.Evaluator.Close()
+	expander.Evaluator = NewBatchEvaluator(expander.Client, batch_size, timeout)
+}
+
+func (expander *PuctExpander) Close() {
+	// Stops the batching goroutine, the expander cannot evaluate positions anymore
+	expander.Evaluator.Close()
+}
+
+func (expander *PuctExpander) SetModelVersion(version string) {
+	expander.ModelVersion.Set(&version)
+}
+
 func (expander *PuctExpander) GetToExpand() chan utils.Triple[MctsNode, int, *environment.Game] {
 	return expander.ToExpand
 }
 
-func (expander *PuctExpander) Expand(to_expand utils.Triple[MctsNode, int, *environment.Game]) {
+func (expander *PuctExpander) Expand(to_expand utils.Triple[MctsNode, int, *environment.Game], priors []float64) {
+	// The game must already be at the position of the child
 	var node MctsNode = to_expand.First
 	var child_idx int = to_expand.Second
 	var game *environment.Game = to_expand.Third
-	game.PlayAction(game.LegalActions[child_idx])
-	var child_node MctsNode = NewPuctNode(game, node, child_idx, expander.Client) // We will set the priors later when we have the neural network evaluation
-	node.GetChildren()[child_idx] = child_node
+	var child_node *PuctNode = NewPuctNode(game, node, child_idx, priors)
+	if expander.Table != nil {
+		expander.Table.Attach(child_node.UctNode)
+	}
+	node.SetChild(child_idx, child_node)
 }
 
-func (agent *PuctExpander) Evaluate(game *environment.Game) utils.Pair[int, []float64] {
-	// just use a dummy evaluation for now, we will replace this with a neural network evaluation later
-	var request remote_trainer.EvaluatePositionRequest = remote_trainer.EvaluatePositionRequest{
-		X: 31,
-		Y: 12,
+func (expander *PuctExpander) NewRequest(game *environment.Game) (*remote_trainer.EvaluatePositionRequest, features.Symmetry) {
+	var height, width int = game.Board.Height, game.Board.Width
+	var symmetry features.Symmetry = 0
+	if expander.RandomSymmetry {
+		symmetry = features.Symmetry(rand.Intn(features.CountSymmetries(height, width)))
 	}
-	response, err := agent.Client.EvaluatePosition(context.Background(), &request)
+	return &remote_trainer.EvaluatePositionRequest{
+		Height:        int32(height),
+		Width:         int32(width),
+		NumPlanes:     features.NumPlanes,
+		Planes:        symmetry.TransformPlanes(features.Encode(game), features.NumPlanes, height, width),
+		LegalMask:     symmetry.TransformMask(features.LegalMask(game), height, width),
+		Symmetry:      int32(symmetry),
+		WantOwnership: false,
+		ModelVersion:  *expander.ModelVersion.Get(),
+	}, symmetry
+}
+
+func (agent *PuctExpander) Evaluate(game *environment.Game) utils.Pair[float64, []float64] {
+	// Value of the position for the player to move and priors of its legal actions
+	if game.IsTerminal() {
+		var value float64 = 0
+		switch game.GetWinner() {
+		case game.Board.CurrentPlayer:
+			value = 1
+		case game.Board.CurrentPlayer.Opponent():
+			value = -1
+		}
+		return utils.NewPair(value, features.UniformPriors(game))
+	}
+
+	request, symmetry := agent.NewRequest(game)
+	response, err := agent.Evaluator.Evaluate(request)
 	if err != nil {
 		println("Error evaluating position:", err.Error())
-		return utils.NewPair(0, make([]float64, 0))
+		return utils.NewPair(0.0, features.UniformPriors(game))
 	}
-	var value int64 = response.Z
-
-	println("Evaluated position with value:", value)
-	return utils.NewPair(0, make([]float64, 0)) // We will set the priors later when we have the neural network evaluation
+	var policy []float32 = symmetry.InvertPolicy(response.Policy, game.Board.Height, game.Board.Width)
+	var value float64 = max(-1, min(1, float64(response.Value)))
+	return utils.NewPair(value, features.Priors(game, policy))
 }
 
-func (agent *PuctExpander) ExpandAndEvaluate(to_expand utils.Triple[MctsNode, int, *environment.Game]) int {
-	println("Expanding and evaluating a node...")
+func (agent *PuctExpander) NewRoot(game *environment.Game) *PuctNode {
+	var evaluation utils.Pair[float64, []float64] = agent.Evaluate(game)
+	return NewPuctNode(game, nil, -1, evaluation.Second)
+}
 
-	agent.Expand(to_expand)
+func (agent *PuctExpander) ExpandAndEvaluate(to_expand utils.Triple[MctsNode, int, *environment.Game]) float64 {
+	// The child is evaluated before it is added to the tree so that no routine selects from it without its priors
+	to_expand.Third.PlayAction(to_expand.Third.LegalActions[to_expand.Second])
 	// Playing the action to reach the expanded child made so the opponent (expanded child) had this final value
-	var evaluation utils.Pair[int, []float64] = agent.Evaluate(to_expand.Third)
-	var value int = evaluation.First
-	var priors []float64 = evaluation.Second
-
-	// Set the priors for the expanded child node
-	var child_node *PuctNode = to_expand.First.GetChildren()[to_expand.Second].(*PuctNode)
-	child_node.P = priors
+	var evaluation utils.Pair[float64, []float64] = agent.Evaluate(to_expand.Third)
+	var value float64 = evaluation.First
+	agent.Expand(to_expand, evaluation.Second)
 	// So the value for the parent node is the negation of this value
 	return -value
 }
@@ -1,34 +1,26 @@
 package agents
 
 import (
-	"context"
 	"math"
 
-	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
 	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/features"
 )
 
 type PuctNode struct {
 	*UctNode
-	P      []float64 // Prior probabilities for each action
-	Client remote_trainer.PositionEvaluatorClient
+	P []float64 // Prior probabilities for each action
 }
 
 // Constructor
-func NewPuctNode(game *environment.Game, parent MctsNode, idx int, client remote_trainer.PositionEvaluatorClient) *PuctNode {
+func NewPuctNode(game *environment.Game, parent MctsNode, idx int, priors []float64) *PuctNode {
+	// Without priors of the right size, all the moves are equally likely
+	if len(priors) != len(game.LegalActions) {
+		priors = features.UniformPriors(game)
+	}
 	return &PuctNode{
-		UctNode: &UctNode{
-			Parent:     parent,
-			Idx:        idx,
-			K:          len(game.LegalActions),
-			TotalN:     0,
-			N:          make([]int, len(game.LegalActions)),
-			Q:          make([]float64, len(game.LegalActions)),
-			Children:   make([]MctsNode, len(game.LegalActions)),
-			IsExpanded: make([]int32, len(game.LegalActions)),
-		},
-		P:      make([]float64, len(game.LegalActions)),
-		Client: client,
+		UctNode: NewUctNode(game, parent, idx),
+		P:       priors,
 	}
 }
 
@@ -37,20 +29,28 @@ func (node *PuctNode) GetParent() MctsNode {
 	return node.Parent
 }
 
+func (node *PuctNode) SetParent(parent MctsNode) {
+	node.Parent = parent
+}
+
 func (node *PuctNode) GetIdx() int {
 	return node.Idx
 }
 
+func (node *PuctNode) GetTotalN() int {
+	return int(node.TotalN.Load())
+}
+
 func (node *PuctNode) GetN() []int {
-	return node.N
+	return node.UctNode.GetN()
 }
 
 func (node *PuctNode) GetQ() []float64 {
-	return node.Q
+	return node.UctNode.GetQ()
 }
 
 func (node *PuctNode) GetChildren() []MctsNode {
-	return node.Children
+	return node.UctNode.GetChildren()
 }
 
 func (node *PuctNode) GetIsExpanded() []int32 {
@@ -58,45 +58,31 @@ func (node *PuctNode) GetIsExpanded() []int32 {
 }
 
 // Methods
-func (node *PuctNode) Reset(game *environment.Game) {
-	node.Mutex.Lock()
-	defer node.Mutex.Unlock()
-
-	node.TotalN = 0
-	for i := 0; i < node.K; i++ {
-		node.N[i] = 0
-		node.Q[i] = 0
-		node.Children[i] = nil
-		node.IsExpanded[i] = 0
-	}
-
-	var request remote_trainer.EvaluatePositionRequest = remote_trainer.EvaluatePositionRequest{
-		X: 31,
-		Y: 12,
-	}
-	response, err := node.Client.EvaluatePosition(context.Background(), &request)
-	if err != nil {
-		println("Error evaluating position:", err.Error())
-		return
-	}
-	var _ int64 = response.Z
+func (node *PuctNode) GetPriorsSnapshot() []float64 {
+	// The priors are only written before the node is searched
+	var priors []float64 = make([]float64, len(node.P))
+	copy(priors, node.P)
+	return priors
+}
 
-	var priors []float64 = make([]float64, 0)
-	node.P = priors
+func (node *PuctNode) Reset(game *environment.Game) {
+	// The priors only depend on the position, they are kept
+	node.UctNode.Reset(game)
 }
 
 func (node *PuctNode) SelectBestChildIndex() int {
-	node.Mutex.Lock()
-	defer node.Mutex.Unlock()
-
 	//By default, we use UCT (Upper Confidence Bound for Trees) with exploration constant sqrt(2)
 
 	var c float64 = 1.0
+	var sqrt_total_n float64 = math.Sqrt(float64(max(1, node.TotalN.Load()))) // At least 1 so that the first visit follows the priors
 	var best_action_idx int
 	var best_value float64 = math.Inf(-1)
 	for action_idx := 0; action_idx < node.K; action_idx++ {
-		var exploration_term float64 = node.P[action_idx] * math.Sqrt(float64(node.TotalN)) / (1 + float64(node.N[action_idx]))
-		var puct_value float64 = node.Q[action_idx] + c*exploration_term
+		var n int
+		var q float64
+		n, q = node.SelectionStats(action_idx)
+		var exploration_term float64 = node.P[action_idx] * sqrt_total_n / (1 + float64(n))
+		var puct_value float64 = q + c*exploration_term
 		if puct_value > best_value {
 			best_value = puct_value
 			best_action_idx = action_idx
@@ -104,17 +90,7 @@ func (node *PuctNode) SelectBestChildIndex() int {
 	}
 
 	// Add virtual loss
-	node.TotalN += 1
-	node.N[best_action_idx] += 1
-	node.Q[best_action_idx] += (-1 - node.Q[best_action_idx]) / float64(node.N[best_action_idx]) // Pessimisticly suppose the value is -1
+	node.AddVirtualLoss(best_action_idx)
 
 	return best_action_idx
 }
-
-func (node *PuctNode) UpdateStats(value int, action_idx int) {
-	node.Mutex.Lock()
-	defer node.Mutex.Unlock()
-
-	// We artificially added a visit which resulted in a value of -1, replace it with the actual value
-	node.Q[action_idx] += float64(value+1) / float64(node.N[action_idx])
-}
@@ -0,0 +1,100 @@
+package agents
+
+import (
+	"math"
+)
+
+// A RAVE schedule gives the weight beta of the all-moves-as-first value against the UCT value of an action
+type RaveSchedule interface {
+	Beta(n int, rave_n int) float64
+}
+
+// Hand-selected schedule (Gelly & Silver 2007): beta = sqrt(K / (3n + K)), RAVE and UCT weigh the same after K visits
+type HandSelectedSchedule struct {
+	K float64
+}
+
+func NewHandSelectedSchedule(k float64) *HandSelectedSchedule {
+	return &HandSelectedSchedule{
+		K: k,
+	}
+}
+
+func (schedule *HandSelectedSchedule) Beta(n int, rave_n int) float64 {
+	return math.Sqrt(schedule.K / (3*float64(n) + schedule.K))
+}
+
+// Minimum MSE schedule (Silver 2009): beta = rave_n / (n + rave_n + 4 b^2 n rave_n), with b the bias of the RAVE values
+type MinimumMseSchedule struct {
+	Bias float64
+}
+
+func NewMinimumMseSchedule(bias float64) *MinimumMseSchedule {
+	return &MinimumMseSchedule{
+		Bias: bias,
+	}
+}
+
+func (schedule *MinimumMseSchedule) Beta(n int, rave_n int) float64 {
+	var fn, frn float64 = float64(n), float64(rave_n)
+	return frn / (fn + frn + 4*schedule.Bias*schedule.Bias*fn*frn)
+}
+
+type RaveConfig struct {
+	Schedule         RaveSchedule
+	Exploration      float64 // UCT exploration constant, RAVE needs much less exploration than plain UCT
+	FirstPlayUrgency float64 // Value of the actions that were never visited nor seen in a simulation
+}
+
+func NewRaveConfig(schedule RaveSchedule, exploration float64, first_play_urgency float64) *RaveConfig {
+	return &RaveConfig{
+		Schedule:         schedule,
+		Exploration:      exploration,
+		FirstPlayUrgency: first_play_urgency,
+	}
+}
+
+func NewDefaultRaveConfig() *RaveConfig {
+	return NewRaveConfig(NewHandSelectedSchedule(1000), 0.3, 1.0)
+}
+
+func NewRaveAgent(simulations_per_move int, nb_routines int, resign_threshold float64, rave *RaveConfig) *MctsAgent {
+	var expander *UctExpander = NewUctExpander(nb_routines)
+	expander.Rave = rave
+	return NewMctsAgent(simulations_per_move, nb_routines, resign_threshold, expander)
+}
+
+func (node *UctNode) SelectBestRaveChildIndex() int {
+	var c float64 = node.Rave.Exploration
+	var total_n int64 = node.TotalN.Load()
+	var best_action_idx int
+	var best_value float64 = math.Inf(-1)
+	for action_idx := 0; action_idx < node.K; action_idx++ {
+		var n, rave_n int
+		var q, rave_q float64
+		n, q = node.SelectionStats(action_idx)
+		rave_n, rave_q = node.ActionRaveStats(action_idx)
+		var value float64
+		switch {
+		case n == 0 && rave_n == 0:
+			value = node.Rave.FirstPlayUrgency
+		case n == 0:
+			value = rave_q
+		case rave_n == 0:
+			value = q
+		default:
+			var beta float64 = node.Rave.Schedule.Beta(n, rave_n)
+			value = (1-beta)*q + beta*rave_q
+		}
+		var exploration_term float64 = c * math.Sqrt(math.Log(float64(total_n+1))/float64(n+1))
+		if value+exploration_term > best_value {
+			best_value = value + exploration_term
+			best_action_idx = action_idx
+		}
+	}
+
+	// Add virtual loss
+	node.AddVirtualLoss(best_action_idx)
+
+	return best_action_idx
+}
@@ -0,0 +1,28 @@
+package agents
+
+import (
+	"time"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+)
+
+// Speed of the search against the number of routines, shared by cmd/bench and the benchmarks of the package
+
+const MaxScalingRoutines int = 32
+
+func ScalingRoutines(max_routines int) []int {
+	// Powers of two up to max_routines
+	var routines []int
+	for nb_routines := 1; nb_routines <= max_routines; nb_routines *= 2 {
+		routines = append(routines, nb_routines)
+	}
+	return routines
+}
+
+func TimeSearch(simulations int, nb_routines int, game *environment.Game) (int, time.Duration) {
+	// Simulations done by a search of the position and its duration, with a new UCT agent so that no tree is reused
+	var agent *MctsAgent = NewUctAgent(simulations, nb_routines, -1)
+	var start time.Time = time.Now()
+	agent.SelectAction(game.DeepCopy())
+	return agent.SimulationsDone.Get(), time.Since(start)
+}
@@ -0,0 +1,177 @@
+package agents
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+)
+
+type CandidateInfo struct {
+	Action  environment.Action
+	Visits  int
+	Value   float64 // Mean value of the action for the player to move, in [-1, 1]
+	Winrate float64 // Value mapped to [0, 1]
+	Prior   float64 // Prior probability of the action, 0 without a policy
+	PV      []environment.Action
+}
+
+type SearchInfo struct {
+	Player               environment.Stone
+	MoveNumber           int
+	BoardHeight          int
+	Candidates           []CandidateInfo // Most visited first
+	RootValue            float64         // Mean value of the root for the player to move, in [-1, 1]
+	RootVisits           int
+	TotalNodes           int
+	Simulations          int // Simulations of the current search
+	Elapsed              time.Duration
+	SimulationsPerSecond float64
+}
+
+func (agent *MctsAgent) PrincipalVariation(node MctsNode, game *environment.Game, max_length int) []environment.Action {
+	// Follow the most visited action from the node, the game is modified
+	var pv []environment.Action = make([]environment.Action, 0, max_length)
+	for node != nil && len(pv) < max_length && !game.IsTerminal() {
+		var visits []int
+		visits, _ = node.GetStatsSnapshot()
+		var best_idx int = -1
+		for action_idx, action_visits := range visits {
+			if action_visits > 0 && (best_idx == -1 || action_visits > visits[best_idx]) {
+				best_idx = action_idx
+			}
+		}
+		if best_idx == -1 {
+			break
+		}
+		var action environment.Action = game.LegalActions[best_idx]
+		pv = append(pv, action)
+		game.PlayAction(action)
+		node = node.GetChild(best_idx)
+	}
+	return pv
+}
+
+// GetSearchInfo takes a snapshot of the root, it can be called while the search is running
+func (agent *MctsAgent) GetSearchInfo(max_candidates int, max_pv_length int) SearchInfo {
+	var root MctsNode = agent.Root
+	var root_game *environment.Game = agent.RootGame
+	var info SearchInfo = SearchInfo{
+		Candidates: make([]CandidateInfo, 0, max_candidates),
+	}
+	if root == nil || root_game == nil {
+		return info
+	}
+
+	var visits []int
+	var values []float64
+	visits, values = root.GetStatsSnapshot()
+	var priors []float64 = nil
+	if puct_root, ok := root.(*PuctNode); ok {
+		priors = puct_root.GetPriorsSnapshot()
+	}
+
+	var order []int = make([]int, 0, len(visits))
+	for action_idx, action_visits := range visits {
+		if action_visits > 0 {
+			order = append(order, action_idx)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool { return visits[order[a]] > visits[order[b]] })
+	if len(order) > max_candidates {
+		order = order[:max_candidates]
+	}
+
+	for _, action_idx := range order {
+		var candidate CandidateInfo = CandidateInfo{
+			Action:  root_game.LegalActions[action_idx],
+			Visits:  visits[action_idx],
+			Value:   values[action_idx],
+			Winrate: (values[action_idx] + 1) / 2,
+			PV:      []environment.Action{root_game.LegalActions[action_idx]},
+		}
+		if action_idx < len(priors) {
+			candidate.Prior = priors[action_idx]
+		}
+		if max_pv_length > 1 {
+			var game *environment.Game = root_game.DeepCopy()
+			game.PlayAction(candidate.Action)
+			candidate.PV = append(candidate.PV, agent.PrincipalVariation(root.GetChild(action_idx), game, max_pv_length-1)...)
+		}
+		info.Candidates = append(info.Candidates, candidate)
+	}
+
+	info.Player = root_game.Board.CurrentPlayer
+	info.MoveNumber = len(root_game.MoveHistory)
+	info.BoardHeight = root_game.Board.Height
+	info.RootValue = root.GetValue()
+	info.RootVisits = root.GetTotalN()
+	info.TotalNodes = agent.NodeCount.Get()
+	info.Simulations = agent.SimulationsDone.Get()
+	info.Elapsed = time.Since(agent.SearchStart)
+	if info.Elapsed > 0 {
+		info.SimulationsPerSecond = float64(info.Simulations) / info.Elapsed.Seconds()
+	}
+	return info
+}
+
+func (agent *MctsAgent) PublishSearchInfo(done chan bool, stopped chan bool) {
+	// Sends a snapshot to InfoCallback every InfoInterval until done is closed
+	defer close(stopped)
+	var ticker *time.Ticker = time.NewTicker(agent.InfoInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			agent.InfoCallback(agent.GetSearchInfo(agent.InfoCandidates, agent.InfoPVLength))
+		}
+	}
+}
+
+func (agent *MctsAgent) SetInfoCallback(callback func(info SearchInfo), interval time.Duration) {
+	agent.InfoCallback = callback
+	agent.InfoInterval = interval
+}
+
+// NewSearchInfoChannel makes the agent send its search info to the returned channel, dropping updates the reader is too slow for
+func (agent *MctsAgent) NewSearchInfoChannel(interval time.Duration) chan SearchInfo {
+	var info_channel chan SearchInfo = make(chan SearchInfo, 1)
+	agent.SetInfoCallback(func(info SearchInfo) {
+		select {
+		case info_channel <- info:
+		default:
+		}
+	}, interval)
+	return info_channel
+}
+
+func (info SearchInfo) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "move %d, value %.3f, %d visits, %d nodes, %d simulations in %s (%.0f sim/s)", info.MoveNumber, info.RootValue, info.RootVisits, info.TotalNodes, info.Simulations, info.Elapsed.Round(time.Millisecond), info.SimulationsPerSecond)
+	for _, candidate := range info.Candidates {
+		fmt.Fprintf(&builder, "\n  %-6s visits %6d winrate %5.1f%% prior %5.1f%% pv", environment.GtpVertex(candidate.Action, info.BoardHeight), candidate.Visits, 100*candidate.Winrate, 100*candidate.Prior)
+		for _, action := range candidate.PV {
+			builder.WriteString(" " + environment.GtpVertex(action, info.BoardHeight))
+		}
+	}
+	return builder.String()
+}
+
+// LzAnalyze formats the info as the answer to the lz-analyze GTP command
+func (info SearchInfo) LzAnalyze() string {
+	var builder strings.Builder
+	for order, candidate := range info.Candidates {
+		if order > 0 {
+			builder.WriteString(" ")
+		}
+		fmt.Fprintf(&builder, "info move %s visits %d winrate %d prior %d order %d pv", environment.GtpVertex(candidate.Action, info.BoardHeight), candidate.Visits, int(10000*candidate.Winrate), int(10000*candidate.Prior), order)
+		for _, action := range candidate.PV {
+			builder.WriteString(" " + environment.GtpVertex(action, info.BoardHeight))
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,88 @@
+package agents
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/utils"
+)
+
+// AlphaZero-style exploration for generating training games
+type SelfPlayConfig struct {
+	DirichletAlpha   float64 // Concentration of the noise mixed into the root priors, 0 disables the noise
+	DirichletEpsilon float64 // Weight of the noise in the root priors
+	Temperature      float64 // Temperature of the sampling over the root visit counts
+	TemperatureMoves int     // The first moves of the game are sampled, the most visited action is played afterwards
+	Seed             int64   // Seed of the noise and of the sampling
+}
+
+func NewSelfPlayConfig(dirichlet_alpha float64, dirichlet_epsilon float64, temperature float64, temperature_moves int, seed int64) *SelfPlayConfig {
+	return &SelfPlayConfig{
+		DirichletAlpha:   dirichlet_alpha,
+		DirichletEpsilon: dirichlet_epsilon,
+		Temperature:      temperature,
+		TemperatureMoves: temperature_moves,
+		Seed:             seed,
+	}
+}
+
+func NewDefaultSelfPlayConfig(board_size int, seed int64) *SelfPlayConfig {
+	// AlphaZero scales alpha inversely to the typical number of legal moves (0.03 on 19x19)
+	var alpha float64 = 0.03 * 361 / float64(board_size*board_size)
+	return NewSelfPlayConfig(alpha, 0.25, 1.0, board_size*board_size/12, seed)
+}
+
+func (agent *MctsAgent) SetSelfPlay(config *SelfPlayConfig) {
+	agent.SelfPlay = config
+	agent.NoisyRoot = nil
+	if config != nil {
+		agent.Rng = rand.New(rand.NewSource(config.Seed))
+	}
+}
+
+func (agent *MctsAgent) AddRootNoise() {
+	// Only nodes with priors can be noised, and a root is noised once even if it is searched again
+	root, ok := agent.Root.(*PuctNode)
+	if !ok || agent.SelfPlay == nil || agent.SelfPlay.DirichletAlpha <= 0 || agent.NoisyRoot == agent.Root {
+		return
+	}
+	var noise []float64 = utils.SampleDirichlet(agent.Rng, agent.SelfPlay.DirichletAlpha, root.K)
+	root.AddNoise(noise, agent.SelfPlay.DirichletEpsilon)
+	agent.NoisyRoot = agent.Root
+}
+
+func (agent *MctsAgent) SampleActionIndex(visits []int, legal_actions []environment.Action) int {
+	// Sample proportionally to N^(1/T), never resigning
+	var weights []float64 = make([]float64, len(visits))
+	var best_idx int = -1
+	var total float64 = 0
+	for action_idx, action_visits := range visits {
+		if _, is_resign := legal_actions[action_idx].(environment.Resign); is_resign {
+			continue
+		}
+		weights[action_idx] = math.Pow(float64(action_visits), 1/agent.SelfPlay.Temperature)
+		total += weights[action_idx]
+		if best_idx < 0 || action_visits > visits[best_idx] {
+			best_idx = action_idx
+		}
+	}
+	if total == 0 {
+		return best_idx // Nothing visited but resigning, play the first other action
+	}
+	return utils.SampleIndex(agent.Rng, weights)
+}
+
+func (agent *MctsAgent) UsesTemperature() bool {
+	if agent.SelfPlay == nil || agent.SelfPlay.Temperature <= 0 || agent.RootGame == nil {
+		return false
+	}
+	return len(agent.RootGame.MoveHistory) < agent.SelfPlay.TemperatureMoves
+}
+
+func (node *PuctNode) AddNoise(noise []float64, epsilon float64) {
+	// Called before the search, the priors are read without synchronization
+	for action_idx := range node.P {
+		node.P[action_idx] = (1-epsilon)*node.P[action_idx] + epsilon*noise[action_idx]
+	}
+}
@@ -0,0 +1,25 @@
+package agents
+
+import (
+	"testing"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+)
+
+func TestSampleActionIndexNeverResigns(t *testing.T) {
+	// Even when resigning has all the visits, the most visited other action is played
+	var agent *MctsAgent = NewUctAgent(1, 1, -1)
+	agent.SetSelfPlay(NewDefaultSelfPlayConfig(5, 1))
+	var legal_actions []environment.Action = []environment.Action{environment.PutStone{I: 2, J: 2}, environment.Pass{}, environment.Resign{}}
+	for _, test := range []struct {
+		visits []int
+		want   int
+	}{
+		{[]int{0, 0, 10}, 0},
+		{[]int{0, 3, 10}, 1},
+	} {
+		if action_idx := agent.SampleActionIndex(test.visits, legal_actions); action_idx != test.want {
+			t.Errorf("visits %v: sampled %v, want %v", test.visits, legal_actions[action_idx], legal_actions[test.want])
+		}
+	}
+}
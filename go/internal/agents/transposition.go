@@ -0,0 +1,94 @@
+package agents
+
+import (
+	"sync"
+)
+
+// Transposition table: the nodes of a position reached through different move orders share their statistics.
+// The tree itself is kept, every node has a single parent, so backpropagation follows the path of the simulation
+// and updates the shared statistics once per visit.
+type TranspositionTable struct {
+	Mutex   sync.Mutex
+	Entries map[uint64]*UctStats
+}
+
+// Constructor
+func NewTranspositionTable() *TranspositionTable {
+	return &TranspositionTable{
+		Entries: make(map[uint64]*UctStats),
+	}
+}
+
+// Methods
+func (table *TranspositionTable) Clear() {
+	table.Mutex.Lock()
+	defer table.Mutex.Unlock()
+	table.Entries = make(map[uint64]*UctStats)
+}
+
+func (table *TranspositionTable) Len() int {
+	table.Mutex.Lock()
+	defer table.Mutex.Unlock()
+	return len(table.Entries)
+}
+
+// Attach makes the node share the statistics of its position. Returns true if the position was already in the table.
+func (table *TranspositionTable) Attach(node *UctNode) bool {
+	table.Mutex.Lock()
+	defer table.Mutex.Unlock()
+
+	if stats, ok := table.Entries[node.Key]; ok && len(stats.Stats) == node.K {
+		node.UctStats = stats
+		return true
+	}
+	table.Entries[node.Key] = node.UctStats
+	return false
+}
+
+// Rebuild keeps only the positions of the subtree of the given root
+func (table *TranspositionTable) Rebuild(root MctsNode) {
+	table.Clear()
+	var register func(node MctsNode)
+	register = func(node MctsNode) {
+		var uct_node *UctNode
+		switch typed_node := node.(type) {
+		case *UctNode:
+			uct_node = typed_node
+		case *PuctNode:
+			uct_node = typed_node.UctNode
+		default:
+			return
+		}
+		table.Entries[uct_node.Key] = uct_node.UctStats
+		for _, child := range node.GetChildren() {
+			if child != nil {
+				register(child)
+			}
+		}
+	}
+	table.Mutex.Lock()
+	defer table.Mutex.Unlock()
+	register(root)
+}
+
+func (agent *MctsAgent) EnableTranspositions() {
+	switch expander := agent.Expander.(type) {
+	case *UctExpander:
+		expander.Table = NewTranspositionTable()
+	case *PuctExpander:
+		expander.Table = NewTranspositionTable()
+	default:
+		panic("Unknown expander type")
+	}
+}
+
+func (agent *MctsAgent) GetTranspositionTable() *TranspositionTable {
+	switch expander := agent.Expander.(type) {
+	case *UctExpander:
+		return expander.Table
+	case *PuctExpander:
+		return expander.Table
+	default:
+		return nil
+	}
+}
@@ -9,11 +9,17 @@ import (
 
 type UctExpander struct {
 	ToExpand chan utils.Triple[MctsNode, int, *environment.Game]
+	Rave     *RaveConfig         // nil for plain UCT
+	Table    *TranspositionTable // nil to search transpositions separately
+	Playout  Agent               // Plays both sides of the simulations
 }
 
 func NewUctExpander(nb_routines int) *UctExpander {
 	return &UctExpander{
 		ToExpand: make(chan utils.Triple[MctsNode, int, *environment.Game], nb_routines),
+		Rave:     nil,
+		Table:    nil,
+		Playout:  NewRandomAgent(),
 	}
 }
 
@@ -21,18 +27,25 @@ func (expander *UctExpander) GetToExpand() chan utils.Triple[MctsNode, int, *env
 	return expander.ToExpand
 }
 
-func (expander *UctExpander) Expand(to_expand utils.Triple[MctsNode, int, *environment.Game]) {
+func (expander *UctExpander) Expand(to_expand utils.Triple[MctsNode, int, *environment.Game]) bool {
+	// Returns true if the position of the child was already visited through another move order
 	var node MctsNode = to_expand.First
 	var child_idx int = to_expand.Second
 	var game *environment.Game = to_expand.Third
 	game.PlayAction(game.LegalActions[child_idx])
-	var child_node MctsNode = NewUctNode(game, node, child_idx)
-	node.GetChildren()[child_idx] = child_node
+	var child_node *UctNode = NewUctNode(game, node, child_idx)
+	child_node.Rave = expander.Rave
+	var is_transposition bool = false
+	if expander.Table != nil {
+		is_transposition = expander.Table.Attach(child_node) && child_node.GetTotalN() > 0
+	}
+	node.SetChild(child_idx, child_node)
+	return is_transposition
 }
 
 func (expander *UctExpander) Evaluate(game *environment.Game) int {
 	var current_player environment.Stone = game.Board.CurrentPlayer
-	var both_players Agent = NewRandomAgent()
+	var both_players Agent = expander.Playout
 	for !game.IsTerminal() {
 		game.PlayAction(both_players.SelectAction(game))
 	}
@@ -45,10 +58,14 @@ func (expander *UctExpander) Evaluate(game *environment.Game) int {
 	}
 }
 
-func (expander *UctExpander) ExpandAndEvaluate(to_expand utils.Triple[MctsNode, int, *environment.Game]) int {
-	expander.Expand(to_expand)
+func (expander *UctExpander) ExpandAndEvaluate(to_expand utils.Triple[MctsNode, int, *environment.Game]) float64 {
+	if expander.Expand(to_expand) {
+		// Reuse the value of the transposition instead of playing a new simulation
+		var child_node MctsNode = to_expand.First.GetChild(to_expand.Second)
+		return -child_node.GetValue()
+	}
 	// Playing the action to reach the expanded child made so the opponent (expanded child) had this final value
-	var value int = expander.Evaluate(to_expand.Third)
+	var value float64 = float64(expander.Evaluate(to_expand.Third))
 	// So the value for the parent node is the negation of this value
 	return -value
 }
@@ -2,35 +2,122 @@ package agents
 
 import (
 	"math"
-	"sync"
+	"sync/atomic"
 
 	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
 )
 
+// The visit count and the value sum of an action are packed in a single word so that they are read and
+// updated together without a lock: the 24 high bits count the visits, the 40 low bits hold the sum of
+// (value + 1) / 2 in fixed point. An action can be visited up to 2^24 times.
+const (
+	VisitsShift  = 40
+	ValueSumMask = 1<<VisitsShift - 1
+	ValueScale   = 1 << 16
+)
+
+func PackVisit(value float64) uint64 {
+	return 1<<VisitsShift | uint64(math.Round((value+1)/2*ValueScale))
+}
+
+func UnpackVisits(packed uint64) (int, float64) {
+	// Returns the visit count and the sum of (value + 1) / 2
+	return int(packed >> VisitsShift), float64(packed&ValueSumMask) / ValueScale
+}
+
+func MeanValue(visits int, value_sum float64) float64 {
+	if visits == 0 {
+		return 0
+	}
+	return 2*value_sum/float64(visits) - 1
+}
+
+// Statistics of the actions of a position, shared by all the nodes of the position when transpositions are merged.
+// All the fields are atomics, the search never locks a node.
+type UctStats struct {
+	TotalN      atomic.Int64    // Total visit count, the simulations still running included
+	Stats       []atomic.Uint64 // Packed visit count and value sum of the finished simulations of each action
+	VirtualLoss []atomic.Int32  // Number of simulations running through each action
+	RaveStats   []atomic.Uint64 // Packed all-moves-as-first visit count and value sum of each action
+}
+
+// Constructor
+func NewUctStats(k int) *UctStats {
+	return &UctStats{
+		Stats:       make([]atomic.Uint64, k),
+		VirtualLoss: make([]atomic.Int32, k),
+		RaveStats:   make([]atomic.Uint64, k),
+	}
+}
+
+// Methods
+func (stats *UctStats) ActionStats(action_idx int) (int, float64) {
+	// Visit count and mean value of the finished simulations of the action
+	var visits int
+	var value_sum float64
+	visits, value_sum = UnpackVisits(stats.Stats[action_idx].Load())
+	return visits, MeanValue(visits, value_sum)
+}
+
+func (stats *UctStats) SelectionStats(action_idx int) (int, float64) {
+	// Visit count and mean value of the action where the running simulations pessimistically count as losses
+	var visits int
+	var value_sum float64
+	visits, value_sum = UnpackVisits(stats.Stats[action_idx].Load())
+	visits += int(stats.VirtualLoss[action_idx].Load())
+	return visits, MeanValue(visits, value_sum) // A loss adds 0 to the sum of (value + 1) / 2
+}
+
+func (stats *UctStats) ActionRaveStats(action_idx int) (int, float64) {
+	var visits int
+	var value_sum float64
+	visits, value_sum = UnpackVisits(stats.RaveStats[action_idx].Load())
+	return visits, MeanValue(visits, value_sum)
+}
+
+func (stats *UctStats) AddVirtualLoss(action_idx int) {
+	stats.TotalN.Add(1)
+	stats.VirtualLoss[action_idx].Add(1)
+}
+
 type UctNode struct {
-	Mutex      sync.Mutex
+	*UctStats
 	Parent     MctsNode
-	Idx        int       // Index of the action taken to reach this node from its parent
-	K          int       // Number of legal actions
-	TotalN     int       // Total visit count
-	N          []int     // Visit counts for each action
-	Q          []float64 // Total reward for each action
-	Children   []MctsNode
-	IsExpanded []int32 // Atomic boolean flags to indicate if child nodes are expanded
+	Idx        int // Index of the action taken to reach this node from its parent
+	K          int // Number of legal actions
+	Children   []atomic.Pointer[MctsNode]
+	IsExpanded []int32     // Atomic boolean flags to indicate if child nodes are expanded
+	Key        uint64      // Transposition key of the position
+	MoveNumber int         // Number of moves played before reaching this node
+	Width      int         // Width of the board, used to index PointIdx
+	PointIdx   []int       // Action index of each point of the board, -1 if putting a stone there is illegal
+	Rave       *RaveConfig // nil for plain UCT
 }
 
 // Constructor
 func NewUctNode(game *environment.Game, parent MctsNode, idx int) *UctNode {
-	return &UctNode{
+	var node *UctNode = &UctNode{
+		UctStats:   NewUctStats(len(game.LegalActions)),
 		Parent:     parent,
 		Idx:        idx,
 		K:          len(game.LegalActions),
-		TotalN:     0,
-		N:          make([]int, len(game.LegalActions)),
-		Q:          make([]float64, len(game.LegalActions)),
-		Children:   make([]MctsNode, len(game.LegalActions)),
+		Children:   make([]atomic.Pointer[MctsNode], len(game.LegalActions)),
 		IsExpanded: make([]int32, len(game.LegalActions)),
+		Key:        game.TranspositionKey(),
+		MoveNumber: len(game.MoveHistory),
+		Width:      game.Board.Width,
+		PointIdx:   make([]int, game.Board.Height*game.Board.Width),
+		Rave:       nil,
+	}
+	for point := range node.PointIdx {
+		node.PointIdx[point] = -1
 	}
+	for action_idx, action := range game.LegalActions {
+		if put_stone, ok := action.(environment.PutStone); ok {
+			node.PointIdx[put_stone.I*node.Width+put_stone.J] = action_idx
+		}
+	}
+	return node
 }
 
 // Getters
@@ -38,20 +125,49 @@ func (node *UctNode) GetParent() MctsNode {
 	return node.Parent
 }
 
+func (node *UctNode) SetParent(parent MctsNode) {
+	node.Parent = parent
+}
+
 func (node *UctNode) GetIdx() int {
 	return node.Idx
 }
 
+func (node *UctNode) GetTotalN() int {
+	return int(node.TotalN.Load())
+}
+
 func (node *UctNode) GetN() []int {
-	return node.N
+	var n []int
+	n, _ = node.GetStatsSnapshot()
+	return n
 }
 
 func (node *UctNode) GetQ() []float64 {
-	return node.Q
+	var q []float64
+	_, q = node.GetStatsSnapshot()
+	return q
+}
+
+func (node *UctNode) GetChild(action_idx int) MctsNode {
+	var child *MctsNode = node.Children[action_idx].Load()
+	if child == nil {
+		return nil
+	}
+	return *child
+}
+
+func (node *UctNode) SetChild(action_idx int, child MctsNode) {
+	// The child is fully built before being published, the routines reading it never see a partial node
+	node.Children[action_idx].Store(&child)
 }
 
 func (node *UctNode) GetChildren() []MctsNode {
-	return node.Children
+	var children []MctsNode = make([]MctsNode, node.K)
+	for action_idx := range children {
+		children[action_idx] = node.GetChild(action_idx)
+	}
+	return children
 }
 
 func (node *UctNode) GetIsExpanded() []int32 {
@@ -59,36 +175,50 @@ func (node *UctNode) GetIsExpanded() []int32 {
 }
 
 // Methods
-func (node *UctNode) Reset(game *environment.Game) {
-	node.Mutex.Lock()
-	defer node.Mutex.Unlock()
+func (node *UctNode) GetStatsSnapshot() ([]int, []float64) {
+	// Visit counts and mean values of the finished simulations, can be read while the search is running
+	var n []int = make([]int, node.K)
+	var q []float64 = make([]float64, node.K)
+	for action_idx := 0; action_idx < node.K; action_idx++ {
+		n[action_idx], q[action_idx] = node.ActionStats(action_idx)
+	}
+	return n, q
+}
 
-	node.TotalN = 0
+func (node *UctNode) Reset(game *environment.Game) {
+	// Must not be called while the node is searched
+	node.TotalN.Store(0)
 	for i := 0; i < node.K; i++ {
-		node.N[i] = 0
-		node.Q[i] = 0
-		node.Children[i] = nil
-		node.IsExpanded[i] = 0
+		node.Stats[i].Store(0)
+		node.VirtualLoss[i].Store(0)
+		node.RaveStats[i].Store(0)
+		node.Children[i].Store(nil)
+		atomic.StoreInt32(&node.IsExpanded[i], 0)
 	}
 }
 
 func (node *UctNode) SelectBestChildIndex() int {
-	node.Mutex.Lock()
-	defer node.Mutex.Unlock()
-
 	//By default, we use UCT (Upper Confidence Bound for Trees) with exploration constant sqrt(2)
+	if node.Rave != nil {
+		return node.SelectBestRaveChildIndex()
+	}
 
+	// Without a lock, routines reading the node at the same time may select the same action before seeing each other's virtual loss
 	var c float64 = math.Sqrt(2)
+	var total_n int64 = node.TotalN.Load()
 	var best_action_idx int
 	var best_value float64 = math.Inf(-1)
 	for action_idx := 0; action_idx < node.K; action_idx++ {
+		var n int
+		var q float64
+		n, q = node.SelectionStats(action_idx)
 		var exploration_term float64
-		if node.N[action_idx] == 0 {
+		if n == 0 {
 			exploration_term = math.Inf(1)
 		} else {
-			exploration_term = c * math.Sqrt(math.Log(float64(node.TotalN))/float64(node.N[action_idx]))
+			exploration_term = c * math.Sqrt(math.Log(float64(total_n))/float64(n))
 		}
-		var uct_value float64 = node.Q[action_idx] + exploration_term
+		var uct_value float64 = q + exploration_term
 		if uct_value > best_value {
 			best_value = uct_value
 			best_action_idx = action_idx
@@ -96,17 +226,54 @@ func (node *UctNode) SelectBestChildIndex() int {
 	}
 
 	// Add virtual loss
-	node.TotalN += 1
-	node.N[best_action_idx] += 1
-	node.Q[best_action_idx] += (-1 - node.Q[best_action_idx]) / float64(node.N[best_action_idx]) // Pessimisticly suppose the value is -1
+	node.AddVirtualLoss(best_action_idx)
 
 	return best_action_idx
 }
 
-func (node *UctNode) UpdateStats(value int, action_idx int) {
-	node.Mutex.Lock()
-	defer node.Mutex.Unlock()
+func (node *UctNode) UpdateStats(value float64, action_idx int) {
+	// The running simulation counted as a loss becomes a finished one with the actual value
+	node.Stats[action_idx].Add(PackVisit(value))
+	node.VirtualLoss[action_idx].Add(-1)
+}
+
+func (node *UctNode) GetValue() float64 {
+	// Mean value of the position for the player to move, over all the finished visits of its actions
+	var total_visits int = 0
+	var total_value_sum float64 = 0
+	for action_idx := 0; action_idx < node.K; action_idx++ {
+		var visits int
+		var value_sum float64
+		visits, value_sum = UnpackVisits(node.Stats[action_idx].Load())
+		total_visits += visits
+		total_value_sum += value_sum
+	}
+	return MeanValue(total_visits, total_value_sum)
+}
+
+func (node *UctNode) RevertVirtualLoss(action_idx int) {
+	// Remove the pessimistic visit added in SelectBestChildIndex, as if it never happened
+	node.VirtualLoss[action_idx].Add(-1)
+	node.TotalN.Add(-1)
+}
+
+func (node *UctNode) UpdateAmafStats(value float64, move_history []environment.Action) {
+	if node.Rave == nil {
+		return
+	}
 
-	// We artificially added a visit which resulted in a value of -1, replace it with the actual value
-	node.Q[action_idx] += float64(value+1) / float64(node.N[action_idx])
+	// Every stone put by the player of this node later in the simulation counts as if it was played first
+	var seen []bool = make([]bool, node.K)
+	for move := node.MoveNumber; move < len(move_history); move += 2 {
+		put_stone, ok := move_history[move].(environment.PutStone)
+		if !ok {
+			continue
+		}
+		var action_idx int = node.PointIdx[put_stone.I*node.Width+put_stone.J]
+		if action_idx == -1 || seen[action_idx] {
+			continue
+		}
+		seen[action_idx] = true
+		node.RaveStats[action_idx].Add(PackVisit(value))
+	}
 }
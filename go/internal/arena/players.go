@@ -0,0 +1,157 @@
+package arena
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/evaluator"
+)
+
+// Named agent configuration, parsed from "name:kind,key=value,...", for example "rave1k:rave,simulations=1000,rave-k=500".
+// Kinds: random, uct, rave and puct. Keys: simulations, routines, transpositions, rave-k, rave-bias (selects the
+// minimum MSE schedule), rave-exploration, playout (random or pattern, for uct and rave), backend (in-process evaluator
+// for puct) and evaluator (server address for puct).
+type PlayerConfig struct {
+	Name    string
+	Kind    string
+	Options map[string]string
+	Mutex   sync.Mutex
+	Client  remote_trainer.PositionEvaluatorClient // Shared by all the games of a puct player
+}
+
+func ParsePlayerConfig(spec string) (*PlayerConfig, error) {
+	name, rest, ok := strings.Cut(spec, ":")
+	if !ok || name == "" {
+		return nil, fmt.Errorf("player %q: expected name:kind[,key=value...]", spec)
+	}
+	var fields []string = strings.Split(rest, ",")
+	var config *PlayerConfig = &PlayerConfig{
+		Name:    name,
+		Kind:    fields[0],
+		Options: make(map[string]string),
+	}
+	switch config.Kind {
+	case "random", "uct", "rave", "puct":
+	default:
+		return nil, fmt.Errorf("player %s: unknown kind %q", name, config.Kind)
+	}
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("player %s: expected key=value, got %q", name, field)
+		}
+		config.Options[key] = value
+	}
+	if playout, ok := config.Options["playout"]; ok {
+		if _, err := agents.NewPlayoutPolicy(playout); err != nil {
+			return nil, fmt.Errorf("player %s: %w", name, err)
+		}
+	}
+	// Catch typos in the numbers before the games start
+	for _, key := range []string{"simulations", "routines", "rave-k", "rave-bias", "rave-exploration", "playouts"} {
+		if _, err := config.Float(key, 0); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+func (config *PlayerConfig) Float(key string, default_value float64) (float64, error) {
+	value, ok := config.Options[key]
+	if !ok {
+		return default_value, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("player %s: %s=%q is not a number", config.Name, key, value)
+	}
+	return parsed, nil
+}
+
+func (config *PlayerConfig) Int(key string, default_value int) int {
+	value, _ := config.Float(key, float64(default_value)) // Checked by ParsePlayerConfig
+	return int(value)
+}
+
+func (config *PlayerConfig) EvaluatorClient() (remote_trainer.PositionEvaluatorClient, error) {
+	config.Mutex.Lock()
+	defer config.Mutex.Unlock()
+	if config.Client != nil {
+		return config.Client, nil
+	}
+	backend, err := evaluator.NewBackend(config.Options["backend"], config.Int("playouts", 8))
+	if config.Options["backend"] == "" {
+		backend, err = evaluator.NewInfluenceBackend(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("player %s: %w", config.Name, err)
+	}
+	if address, ok := config.Options["evaluator"]; ok {
+		config.Client, _, err = evaluator.Dial(address, backend)
+	} else {
+		config.Client, _, err = evaluator.NewInProcessClient(backend)
+	}
+	return config.Client, err
+}
+
+func (config *PlayerConfig) NewAgent() (agents.Agent, error) {
+	var simulations int = config.Int("simulations", 1000)
+	var routines int = config.Int("routines", 2)
+	var agent *agents.MctsAgent
+	switch config.Kind {
+	case "random":
+		return agents.NewRandomAgent(), nil
+	case "uct":
+		agent = agents.NewUctAgent(simulations, routines, -0.95)
+	case "rave":
+		var rave *agents.RaveConfig = agents.NewDefaultRaveConfig()
+		if _, ok := config.Options["rave-bias"]; ok {
+			bias, _ := config.Float("rave-bias", 0)
+			rave.Schedule = agents.NewMinimumMseSchedule(bias)
+		} else {
+			k, _ := config.Float("rave-k", 1000)
+			rave.Schedule = agents.NewHandSelectedSchedule(k)
+		}
+		rave.Exploration, _ = config.Float("rave-exploration", rave.Exploration)
+		agent = agents.NewRaveAgent(simulations, routines, -0.95, rave)
+	case "puct":
+		client, err := config.EvaluatorClient()
+		if err != nil {
+			return nil, err
+		}
+		agent = agents.NewPuctAgent(simulations, routines, -0.95, client)
+	}
+	if playout, ok := config.Options["playout"]; ok {
+		policy, _ := agents.NewPlayoutPolicy(playout) // Checked by ParsePlayerConfig
+		agent.SetPlayoutPolicy(policy)
+	}
+	if config.Options["transpositions"] == "true" {
+		agent.EnableTranspositions()
+	}
+	return agent, nil
+}
+
+func NewInteractivePlayer(spec string, name string) (agents.Agent, error) {
+	// "human", or a player spec without its name such as "uct,simulations=2000", for the interfaces where people
+	// watch the games: the bots ponder with half of their routines and stop early on decided moves, as in the window
+	if spec == "human" {
+		return agents.NewHumanAgent(), nil
+	}
+	config, err := ParsePlayerConfig(name + ":" + spec)
+	if err != nil {
+		return nil, err
+	}
+	agent, err := config.NewAgent()
+	if err != nil {
+		return nil, err
+	}
+	if mcts_agent, ok := agent.(*agents.MctsAgent); ok {
+		mcts_agent.SetPondering(max(1, config.Int("routines", 2)/2), 200000)
+		mcts_agent.EarlyStopping = true
+	}
+	return agent, nil
+}
@@ -0,0 +1,150 @@
+package arena
+
+import (
+	"math"
+)
+
+type Score struct {
+	Wins   int
+	Losses int
+	Draws  int
+}
+
+func (score Score) Games() int {
+	return score.Wins + score.Losses + score.Draws
+}
+
+func (score Score) WinRate() float64 {
+	// Draws count as half a win
+	if score.Games() == 0 {
+		return 0.5
+	}
+	return (float64(score.Wins) + 0.5*float64(score.Draws)) / float64(score.Games())
+}
+
+func (score Score) WinRateInterval(z float64) (float64, float64) {
+	// Wilson score interval, which stays inside [0, 1] even for lopsided results
+	var n float64 = float64(score.Games())
+	if n == 0 {
+		return 0, 1
+	}
+	var p float64 = score.WinRate()
+	var center float64 = (p + z*z/(2*n)) / (1 + z*z/n)
+	var half_width float64 = z * math.Sqrt(p*(1-p)/n+z*z/(4*n*n)) / (1 + z*z/n)
+	return max(0, center-half_width), min(1, center+half_width)
+}
+
+func EloFromWinRate(win_rate float64) float64 {
+	// Elo difference giving this expected score, clamped to avoid infinities on perfect results
+	win_rate = max(1e-3, min(1-1e-3, win_rate))
+	return -400 * math.Log10(1/win_rate-1)
+}
+
+func WinRateFromElo(elo float64) float64 {
+	return 1 / (1 + math.Pow(10, -elo/400))
+}
+
+// Bradley-Terry ratings of several players, fitted with the minorization-maximization algorithm of Hunter (2004).
+// As in BayesElo, each player gets Prior virtual draws against a virtual player of rating 0, which keeps
+// the ratings finite for players that won or lost all their games.
+type Ratings struct {
+	Elo   []float64
+	Error []float64 // Standard deviation of each rating
+}
+
+func EstimateRatings(results [][]Score, prior float64) Ratings {
+	// results[i][j] is the score of player i against player j
+	var n int = len(results)
+	var gamma []float64 = make([]float64, n)
+	for i := range gamma {
+		gamma[i] = 1
+	}
+	for iteration := 0; iteration < 1000; iteration++ {
+		var max_change float64 = 0
+		for i := 0; i < n; i++ {
+			var wins float64 = prior / 2 // Half of the virtual draws
+			var denominator float64 = prior / (gamma[i] + 1)
+			for j := 0; j < n; j++ {
+				if i == j {
+					continue
+				}
+				var score Score = results[i][j]
+				var games float64 = float64(score.Games() + results[j][i].Games())
+				wins += float64(score.Wins+results[j][i].Losses) + 0.5*float64(score.Draws+results[j][i].Draws)
+				if games > 0 {
+					denominator += games / (gamma[i] + gamma[j])
+				}
+			}
+			var new_gamma float64 = gamma[i]
+			if denominator > 0 && wins > 0 {
+				new_gamma = wins / denominator
+			}
+			max_change = max(max_change, math.Abs(math.Log(new_gamma/gamma[i])))
+			gamma[i] = new_gamma
+		}
+		if max_change < 1e-9 {
+			break
+		}
+	}
+
+	// Errors from the Fisher information of the log-likelihood, on the natural log scale of gamma
+	var ratings Ratings = Ratings{Elo: make([]float64, n), Error: make([]float64, n)}
+	var elo_per_log float64 = 400 / math.Ln10
+	for i := 0; i < n; i++ {
+		var information float64 = prior * gamma[i] / ((gamma[i] + 1) * (gamma[i] + 1))
+		for j := 0; j < n; j++ {
+			if i == j {
+				continue
+			}
+			var games float64 = float64(results[i][j].Games() + results[j][i].Games())
+			information += games * gamma[i] * gamma[j] / ((gamma[i] + gamma[j]) * (gamma[i] + gamma[j]))
+		}
+		ratings.Elo[i] = elo_per_log * math.Log(gamma[i])
+		ratings.Error[i] = math.Inf(1)
+		if information > 0 {
+			ratings.Error[i] = elo_per_log / math.Sqrt(information)
+		}
+	}
+	return ratings
+}
+
+// Sequential probability ratio test between the hypotheses "the Elo difference is Elo0" and "it is Elo1"
+type Sprt struct {
+	Elo0  float64
+	Elo1  float64
+	Alpha float64 // Probability of accepting Elo1 when Elo0 is true
+	Beta  float64 // Probability of accepting Elo0 when Elo1 is true
+}
+
+type SprtResult int
+
+const (
+	SprtContinue SprtResult = iota
+	SprtAcceptH0
+	SprtAcceptH1
+)
+
+func (sprt *Sprt) LogLikelihoodRatio(score Score) float64 {
+	// Draws are split between a win and a loss
+	var p0, p1 float64 = WinRateFromElo(sprt.Elo0), WinRateFromElo(sprt.Elo1)
+	var wins float64 = float64(score.Wins) + 0.5*float64(score.Draws)
+	var losses float64 = float64(score.Losses) + 0.5*float64(score.Draws)
+	return wins*math.Log(p1/p0) + losses*math.Log((1-p1)/(1-p0))
+}
+
+func (sprt *Sprt) Bounds() (float64, float64) {
+	return math.Log(sprt.Beta / (1 - sprt.Alpha)), math.Log((1 - sprt.Beta) / sprt.Alpha)
+}
+
+func (sprt *Sprt) Test(score Score) SprtResult {
+	var llr float64 = sprt.LogLikelihoodRatio(score)
+	var lower, upper float64 = sprt.Bounds()
+	switch {
+	case llr <= lower:
+		return SprtAcceptH0
+	case llr >= upper:
+		return SprtAcceptH1
+	default:
+		return SprtContinue
+	}
+}
@@ -0,0 +1,237 @@
+package arena
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+)
+
+type Settings struct {
+	Size         int
+	Komi         float64
+	OpeningMoves int // Random moves played before the agents take over, the same opening is used for both colors
+	Parallel     int // Games played at the same time
+	Seed         int64
+}
+
+// Games between two players. Player A is black in the even games and white in the odd ones.
+type Pairing struct {
+	A      int
+	B      int
+	Games  int
+	Sprt   *Sprt // Optional early stopping
+	Score  Score // Score of A against B
+	Result SprtResult
+	Played int // Games started so far
+}
+
+func RoundRobin(nb_players int, games int) []*Pairing {
+	var pairings []*Pairing
+	for a := 0; a < nb_players; a++ {
+		for b := a + 1; b < nb_players; b++ {
+			pairings = append(pairings, &Pairing{A: a, B: b, Games: games})
+		}
+	}
+	return pairings
+}
+
+func Gauntlet(nb_players int, games int) []*Pairing {
+	// The first player against each of the others
+	var pairings []*Pairing
+	for b := 1; b < nb_players; b++ {
+		pairings = append(pairings, &Pairing{A: 0, B: b, Games: games})
+	}
+	return pairings
+}
+
+type GameOutcome struct {
+	Pairing *Pairing
+	Black   int
+	White   int
+	Winner  environment.Stone
+	Moves   int
+	Game    *environment.Game // Final position
+	Err     error
+}
+
+type Tournament struct {
+	Players  []*PlayerConfig
+	Pairings []*Pairing
+	Settings Settings
+	Results  [][]Score // Results[i][j] is the score of player i against player j
+	Mutex    sync.Mutex
+	OnGame   func(outcome GameOutcome, tournament *Tournament) // Called after each game, with the mutex held
+}
+
+// Constructor
+func NewTournament(players []*PlayerConfig, pairings []*Pairing, settings Settings) *Tournament {
+	var results [][]Score = make([][]Score, len(players))
+	for i := range results {
+		results[i] = make([]Score, len(players))
+	}
+	return &Tournament{
+		Players:  players,
+		Pairings: pairings,
+		Settings: settings,
+		Results:  results,
+	}
+}
+
+// Methods
+func RandomOpening(size int, komi float64, moves int, rng *rand.Rand) []environment.Action {
+	// Random stones, never passes nor resignations
+	var game *environment.Game = environment.NewGame(size, size, komi)
+	var opening []environment.Action = make([]environment.Action, 0, moves)
+	for len(opening) < moves && !game.IsTerminal() {
+		var stones []environment.Action = game.LegalActions[2:] // Skip resign and pass
+		if len(stones) == 0 {
+			break
+		}
+		var action environment.Action = stones[rng.Intn(len(stones))]
+		game.PlayAction(action)
+		opening = append(opening, action)
+	}
+	return opening
+}
+
+func PlayGame(black_agent, white_agent agents.Agent, size int, komi float64, opening []environment.Action) *environment.Game {
+	var game *environment.Game = environment.NewGame(size, size, komi)
+	for _, action := range opening {
+		game.PlayAction(action)
+	}
+	for !game.IsTerminal() && len(game.MoveHistory) < 4*size*size {
+		var current_agent agents.Agent = black_agent
+		if game.Board.CurrentPlayer == environment.White {
+			current_agent = white_agent
+		}
+		game.PlayAction(current_agent.SelectAction(game.DeepCopy()))
+	}
+	return game
+}
+
+type job struct {
+	Pairing *Pairing
+	Black   int
+	White   int
+	Opening []environment.Action
+}
+
+func (tournament *Tournament) NextJobs(rng *rand.Rand) []job {
+	// The next pair of games of every pairing still running, so that the pairings progress together
+	tournament.Mutex.Lock()
+	defer tournament.Mutex.Unlock()
+	var jobs []job
+	for _, pairing := range tournament.Pairings {
+		if pairing.Result != SprtContinue || pairing.Played >= pairing.Games {
+			continue
+		}
+		var opening []environment.Action = RandomOpening(tournament.Settings.Size, tournament.Settings.Komi, tournament.Settings.OpeningMoves, rng)
+		jobs = append(jobs, job{Pairing: pairing, Black: pairing.A, White: pairing.B, Opening: opening})
+		if pairing.Played+1 < pairing.Games {
+			jobs = append(jobs, job{Pairing: pairing, Black: pairing.B, White: pairing.A, Opening: opening})
+		}
+		pairing.Played += min(2, pairing.Games-pairing.Played)
+	}
+	return jobs
+}
+
+func (tournament *Tournament) Play(job job) GameOutcome {
+	var outcome GameOutcome = GameOutcome{Pairing: job.Pairing, Black: job.Black, White: job.White}
+	// The agents are built for this game only
+	black_agent, err := tournament.Players[job.Black].NewAgent()
+	if err != nil {
+		outcome.Err = err
+		return outcome
+	}
+	if closer, ok := black_agent.(agents.Closer); ok {
+		defer closer.Close()
+	}
+	white_agent, err := tournament.Players[job.White].NewAgent()
+	if err != nil {
+		outcome.Err = err
+		return outcome
+	}
+	if closer, ok := white_agent.(agents.Closer); ok {
+		defer closer.Close()
+	}
+	var game *environment.Game = PlayGame(black_agent, white_agent, tournament.Settings.Size, tournament.Settings.Komi, job.Opening)
+	outcome.Game = game
+	outcome.Winner = game.GetWinner()
+	outcome.Moves = len(game.MoveHistory)
+	return outcome
+}
+
+func (tournament *Tournament) Record(outcome GameOutcome) {
+	tournament.Mutex.Lock()
+	defer tournament.Mutex.Unlock()
+	if outcome.Err == nil {
+		var pairing *Pairing = outcome.Pairing
+		if outcome.Winner == environment.Empty {
+			tournament.Results[outcome.Black][outcome.White].Draws++
+			tournament.Results[outcome.White][outcome.Black].Draws++
+			pairing.Score.Draws++
+		} else {
+			var winner, loser int = outcome.Black, outcome.White
+			if outcome.Winner == environment.White {
+				winner, loser = outcome.White, outcome.Black
+			}
+			tournament.Results[winner][loser].Wins++
+			tournament.Results[loser][winner].Losses++
+			if winner == pairing.A {
+				pairing.Score.Wins++
+			} else {
+				pairing.Score.Losses++
+			}
+		}
+		// Games already running when the test concludes are still counted, but no new game starts
+		if pairing.Sprt != nil && pairing.Result == SprtContinue {
+			pairing.Result = pairing.Sprt.Test(pairing.Score)
+		}
+	}
+	if tournament.OnGame != nil {
+		tournament.OnGame(outcome, tournament)
+	}
+}
+
+func (tournament *Tournament) Run() error {
+	var rng *rand.Rand = rand.New(rand.NewSource(tournament.Settings.Seed))
+	var jobs chan job = make(chan job)
+	var first_err error
+	var err_once sync.Once
+	var wg sync.WaitGroup
+	for worker := 0; worker < max(1, tournament.Settings.Parallel); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				var outcome GameOutcome = tournament.Play(job)
+				if outcome.Err != nil {
+					err_once.Do(func() { first_err = outcome.Err })
+				}
+				tournament.Record(outcome)
+			}
+		}()
+	}
+
+	// New games are only scheduled once a worker is free, so that the SPRT stops a pairing as soon as possible
+	for {
+		var next []job = tournament.NextJobs(rng)
+		if len(next) == 0 {
+			break
+		}
+		for _, job := range next {
+			jobs <- job
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return first_err
+}
+
+func (tournament *Tournament) Ratings(prior float64) Ratings {
+	tournament.Mutex.Lock()
+	defer tournament.Mutex.Unlock()
+	return EstimateRatings(tournament.Results, prior)
+}
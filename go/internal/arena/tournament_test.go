@@ -0,0 +1,58 @@
+package arena
+
+import (
+	"math"
+	"testing"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+)
+
+func TestRecordDraws(t *testing.T) {
+	var tournament *Tournament = NewTournament(make([]*PlayerConfig, 2), RoundRobin(2, 4), Settings{})
+	var pairing *Pairing = tournament.Pairings[0]
+
+	// Two passes on an empty board with an integer komi: the scores are equal
+	var game *environment.Game = PlayGame(nil, nil, 5, 0, []environment.Action{environment.Pass{}, environment.Pass{}})
+	if winner := game.GetWinner(); winner != environment.Empty {
+		t.Fatalf("winner of a drawn game is %v, want Empty", winner)
+	}
+
+	for _, outcome := range []GameOutcome{
+		{Pairing: pairing, Black: 0, White: 1, Winner: game.GetWinner()},
+		{Pairing: pairing, Black: 1, White: 0, Winner: environment.Empty},
+		{Pairing: pairing, Black: 1, White: 0, Winner: environment.White}, // A wins
+		{Pairing: pairing, Black: 1, White: 0, Winner: environment.Black}, // B wins
+	} {
+		tournament.Record(outcome)
+	}
+	var expected Score = Score{Wins: 1, Losses: 1, Draws: 2}
+	if pairing.Score != expected {
+		t.Errorf("pairing score is %+v, want %+v", pairing.Score, expected)
+	}
+	if tournament.Results[0][1] != expected || tournament.Results[1][0] != expected {
+		t.Errorf("results are %+v and %+v, want %+v for both players", tournament.Results[0][1], tournament.Results[1][0], expected)
+	}
+}
+
+func TestEstimateRatingsDraws(t *testing.T) {
+	// Only draws: equal ratings
+	var drawn Ratings = EstimateRatings([][]Score{{{}, {Draws: 10}}, {{Draws: 10}, {}}}, 2)
+	if math.Abs(drawn.Elo[0]-drawn.Elo[1]) > 1e-6 {
+		t.Errorf("ratings after draws only are %v", drawn.Elo)
+	}
+	if math.IsInf(drawn.Error[0], 0) {
+		t.Errorf("no error estimate after 20 games")
+	}
+
+	// A draw counts as half a win and half a loss: 6-2-4 rates as 8-4
+	var with_draws Ratings = EstimateRatings([][]Score{{{}, {Wins: 6, Losses: 2, Draws: 4}}, {{Wins: 2, Losses: 6, Draws: 4}, {}}}, 2)
+	var without_draws Ratings = EstimateRatings([][]Score{{{}, {Wins: 8, Losses: 4}}, {{Wins: 4, Losses: 8}, {}}}, 2)
+	for player := range with_draws.Elo {
+		if math.Abs(with_draws.Elo[player]-without_draws.Elo[player]) > 1e-6 {
+			t.Errorf("player %d: rating %f with draws, %f with the draws split", player, with_draws.Elo[player], without_draws.Elo[player])
+		}
+	}
+	if with_draws.Elo[0] <= with_draws.Elo[1] {
+		t.Errorf("the player with more wins is rated %f, below %f", with_draws.Elo[0], with_draws.Elo[1])
+	}
+}
@@ -1,5 +1,7 @@
 package environment
 
+import "strconv"
+
 type Action interface {
 	IsAction()
 	String() string
@@ -30,3 +32,18 @@ func (r Resign) IsAction() {}
 func (r Resign) String() string {
 	return "Resign"
 }
+
+// GTP coordinates of an action, columns skip the letter I and rows are counted from the bottom of the board
+func GtpVertex(action Action, height int) string {
+	const columns string = "ABCDEFGHJKLMNOPQRSTUVWXYZ"
+	switch a := action.(type) {
+	case PutStone:
+		return string(columns[a.J]) + strconv.Itoa(height-a.I)
+	case Pass:
+		return "pass"
+	case Resign:
+		return "resign"
+	default:
+		return "unknown"
+	}
+}
@@ -19,6 +19,7 @@ type Board struct {
 	CurrentPlayer Stone
 	Passes        Passes
 	Resigned      Stone
+	Captures      Captures
 	UnionFind     *UnionFind
 }
 
@@ -47,6 +48,7 @@ func (b *Board) DeepCopy() *Board {
 		CurrentPlayer: b.CurrentPlayer,
 		Passes:        b.Passes,
 		Resigned:      b.Resigned,
+		Captures:      b.Captures,
 		UnionFind:     b.UnionFind.DeepCopy(),
 	}
 	for i := range b.Matrix {
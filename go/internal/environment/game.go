@@ -1,5 +1,7 @@
 package environment
 
+import "math/bits"
+
 type Score struct {
 	Black float64
 	White float64
@@ -18,6 +20,8 @@ type Game struct {
 	Board        *Board
 	LegalActions []Action
 	BoardHasher  *BoardHasher
+	MoveHistory  []Action // Actions played since the start of the game
+	Rules        Ruleset
 }
 
 // Constructor
@@ -27,20 +31,48 @@ func NewGame(height, width int, komi float64) *Game {
 		Board:        NewBoard(height, width),
 		LegalActions: make([]Action, 0),
 		BoardHasher:  NewBoardHasher(height, width),
+		MoveHistory:  make([]Action, 0),
+		Rules:        AreaScoring,
 	}
 	game.ComputeLegalActions()
 	game.BoardHasher.UpdateHashHistory()
 	return game
 }
 
+func NewGameFromPosition(matrix [][]Stone, current_player Stone, komi float64) *Game {
+	// Game starting from the given stones, the position must be legal (every group has a liberty)
+	var game *Game = NewGame(len(matrix), len(matrix[0]), komi)
+	for i := range matrix {
+		for j, stone := range matrix[i] {
+			if stone == Empty {
+				continue
+			}
+			game.Board.CurrentPlayer = stone
+			game.PutStone(i, j)
+		}
+	}
+	game.Board.CurrentPlayer = current_player
+	if current_player == White {
+		game.BoardHasher.UpdateHash(0, 0, Empty, Empty, true)
+	}
+	// The setup is the first position of the history
+	game.BoardHasher.HashHistory = game.BoardHasher.HashHistory[:0]
+	game.BoardHasher.UpdateHashHistory()
+	game.ComputeLegalActions()
+	return game
+}
+
 func (game *Game) DeepCopy() *Game {
 	var game_copy *Game = &Game{
 		Komi:         game.Komi,
 		Board:        game.Board.DeepCopy(),
 		LegalActions: make([]Action, len(game.LegalActions)),
 		BoardHasher:  game.BoardHasher.DeepCopy(),
+		MoveHistory:  make([]Action, len(game.MoveHistory)),
+		Rules:        game.Rules,
 	}
 	copy(game_copy.LegalActions, game.LegalActions)
+	copy(game_copy.MoveHistory, game.MoveHistory)
 	return game_copy
 }
 
@@ -95,15 +127,19 @@ func (game *Game) ComputeScore() Score {
 		for j := 0; j < game.Board.Width; j++ {
 			switch game.Board.Matrix[i][j] {
 			case Black:
-				black_score += 1.0
+				if game.Rules == AreaScoring {
+					black_score += 1.0
+				}
 			case White:
-				white_score += 1.0
+				if game.Rules == AreaScoring {
+					white_score += 1.0
+				}
 			case Empty:
 				if is_black[i][j] && !is_white[i][j] {
 					black_score += 1.0
 				} else if is_white[i][j] && !is_black[i][j] {
 					white_score += 1.0
-				} else {
+				} else if game.Rules == AreaScoring {
 					black_score += 0.5
 					white_score += 0.5
 				}
@@ -111,6 +147,11 @@ func (game *Game) ComputeScore() Score {
 		}
 	}
 
+	if game.Rules == TerritoryScoring {
+		black_score += float64(game.Board.Captures.Black)
+		white_score += float64(game.Board.Captures.White)
+	}
+
 	return NewScore(black_score, white_score)
 }
 
@@ -121,11 +162,46 @@ func (game *Game) GetWinner() Stone {
 	var score Score = game.ComputeScore()
 	if score.Black > score.White {
 		return Black
-	} else {
+	} else if score.White > score.Black {
 		return White
+	} else {
+		return Empty // Draw, only possible with an integer komi
 	}
 }
 
+func (game *Game) GameResult(player Stone) float64 {
+	// Final result of the game for the given player: 1 for a win, -1 for a loss, 0 for a draw
+	switch game.GetWinner() {
+	case player:
+		return 1
+	case Empty:
+		return 0
+	default:
+		return -1
+	}
+}
+
+func (game *Game) TranspositionKey() uint64 {
+	// Two games with the same key can be searched as one: same stones, player to move, passes and legal moves.
+	// The legal moves depend on the history of the game through superko, so they are part of the key.
+	var key uint64 = game.BoardHasher.BoardHash
+	for _, action := range game.LegalActions {
+		if put_stone, ok := action.(PutStone); ok {
+			key ^= bits.RotateLeft64(game.BoardHasher.ZobristTable[put_stone.I][put_stone.J][0], 31)
+		}
+	}
+	if game.Board.Passes.Black {
+		key ^= bits.RotateLeft64(game.BoardHasher.PlayerHash, 7)
+	}
+	if game.Board.Passes.White {
+		key ^= bits.RotateLeft64(game.BoardHasher.PlayerHash, 13)
+	}
+	if game.Board.Resigned != Empty {
+		key ^= bits.RotateLeft64(game.BoardHasher.PlayerHash, 19+int(game.Board.Resigned))
+	}
+	return key
+}
+
 func (game *Game) IsTerminal() bool {
 	return (game.Board.Passes.Black && game.Board.Passes.White) || game.Board.Resigned != Empty
 }
@@ -206,6 +282,26 @@ func (game *Game) IsLegalAction(i, j int) bool {
 	return true
 }
 
+func (game *Game) KoPoints() []Position {
+	// Empty points where the move would capture but is forbidden by superko
+	var ko_points []Position
+	for i := 0; i < game.Board.Height; i++ {
+		for j := 0; j < game.Board.Width; j++ {
+			if game.Board.Matrix[i][j] != Empty || game.IsLegalAction(i, j) {
+				continue
+			}
+			_, _, enemy_shared_liberties := game.GetNeighboringLiberties(i, j)
+			for enemy_root, shared_liberties := range enemy_shared_liberties {
+				if game.Board.UnionFind.Groups[enemy_root].Liberties-shared_liberties == 0 {
+					ko_points = append(ko_points, NewPosition(i, j))
+					break
+				}
+			}
+		}
+	}
+	return ko_points
+}
+
 func (game *Game) ComputeLegalActions() {
 	var legal_actions []Action = make([]Action, 0, game.Board.Height*game.Board.Width+1)
 	// Add resign action
@@ -225,6 +321,12 @@ func (game *Game) ComputeLegalActions() {
 
 func (game *Game) CaptureGroup(captured_group *Group) {
 	var captured_stones map[Position]Stone = game.Board.GetCapturedStones(captured_group)
+	switch game.Board.CurrentPlayer {
+	case Black:
+		game.Board.Captures.Black += len(captured_stones)
+	case White:
+		game.Board.Captures.White += len(captured_stones)
+	}
 	for pos, stone := range captured_stones {
 		var i, j int = pos.First, pos.Second
 		// Remove stone from board and update board hash
@@ -302,6 +404,7 @@ func (game *Game) PlayAction(action Action) {
 			game.Board.Resigned = White
 		}
 	}
+	game.MoveHistory = append(game.MoveHistory, action)
 
 	// Switch current player
 	if game.Board.CurrentPlayer == Black {
@@ -0,0 +1,101 @@
+package environment
+
+import "fmt"
+
+// Scoring rules, both use positional superko
+type Ruleset int
+
+const (
+	AreaScoring      Ruleset = iota // Chinese: stones plus surrounded points
+	TerritoryScoring                // Japanese: surrounded points plus prisoners
+)
+
+var Rulesets []Ruleset = []Ruleset{AreaScoring, TerritoryScoring}
+
+func (rules Ruleset) String() string {
+	switch rules {
+	case AreaScoring:
+		return "chinese"
+	case TerritoryScoring:
+		return "japanese"
+	default:
+		return "unknown"
+	}
+}
+
+func ParseRuleset(name string) (Ruleset, error) {
+	for _, rules := range Rulesets {
+		if rules.String() == name {
+			return rules, nil
+		}
+	}
+	return AreaScoring, fmt.Errorf("unknown ruleset %q, expected chinese or japanese", name)
+}
+
+// Number of stones captured by each player
+type Captures struct {
+	Black int
+	White int
+}
+
+func MaxHandicap(size int) int {
+	switch {
+	case size < 7:
+		return 0
+	case size == 7 || size%2 == 0:
+		return 4 // No center nor side points
+	default:
+		return 9
+	}
+}
+
+func HandicapPoints(size int, stones int) []Position {
+	// Fixed placement of the GTP specification: corners first, then the center and the sides
+	stones = min(stones, MaxHandicap(size))
+	if stones < 2 {
+		return nil
+	}
+	var edge int = 2
+	if size >= 13 {
+		edge = 3
+	}
+	var low, middle, high int = edge, size / 2, size - 1 - edge
+	// Rows are counted from the top, the first stones go to the upper right and lower left corners
+	var corners []Position = []Position{NewPosition(low, high), NewPosition(high, low), NewPosition(high, high), NewPosition(low, low)}
+	var center Position = NewPosition(middle, middle)
+	var sides []Position = []Position{NewPosition(middle, low), NewPosition(middle, high), NewPosition(low, middle), NewPosition(high, middle)}
+	switch stones {
+	case 2, 3, 4:
+		return corners[:stones]
+	case 5:
+		return append(corners, center)
+	case 6:
+		return append(corners, sides[:2]...)
+	case 7:
+		return append(append(corners, sides[:2]...), center)
+	case 8:
+		return append(corners, sides...)
+	default:
+		return append(append(corners, sides...), center)
+	}
+}
+
+func NewHandicapGame(size int, komi float64, handicap int, rules Ruleset) *Game {
+	// Black's handicap stones are already on the board and White plays first
+	var points []Position = HandicapPoints(size, handicap)
+	var game *Game
+	if len(points) == 0 {
+		game = NewGame(size, size, komi)
+	} else {
+		var matrix [][]Stone = make([][]Stone, size)
+		for i := range matrix {
+			matrix[i] = make([]Stone, size)
+		}
+		for _, point := range points {
+			matrix[point.First][point.Second] = Black
+		}
+		game = NewGameFromPosition(matrix, White, komi)
+	}
+	game.Rules = rules
+	return game
+}
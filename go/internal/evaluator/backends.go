@@ -0,0 +1,173 @@
+package evaluator
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+)
+
+// A backend plays the role of the network: it evaluates a position seen from the player to move
+type Backend interface {
+	Evaluate(position *Position, want_ownership bool) *remote_trainer.EvaluatePositionResponse
+}
+
+func NewBackend(name string, playouts int) (Backend, error) {
+	switch name {
+	case "uniform":
+		return &UniformBackend{}, nil
+	case "playout":
+		return &PlayoutBackend{Playouts: playouts}, nil
+	case "influence":
+		return NewInfluenceBackend(), nil
+	default:
+		return nil, fmt.Errorf("unknown backend %q, expected uniform, playout or influence", name)
+	}
+}
+
+// Uniform backend: every legal move is equally likely and the position is balanced
+type UniformBackend struct{}
+
+func (backend *UniformBackend) Evaluate(position *Position, want_ownership bool) *remote_trainer.EvaluatePositionResponse {
+	var response *remote_trainer.EvaluatePositionResponse = &remote_trainer.EvaluatePositionResponse{
+		Policy: position.MaskedPolicy(make([]float64, len(position.LegalMask))),
+		Value:  0,
+	}
+	if want_ownership {
+		response.Ownership = make([]float32, position.Height*position.Width)
+	}
+	return response
+}
+
+// Playout backend: uniform priors, the value, score and ownership are averaged over random playouts
+type PlayoutBackend struct {
+	Playouts int
+}
+
+func (backend *PlayoutBackend) Evaluate(position *Position, want_ownership bool) *remote_trainer.EvaluatePositionResponse {
+	var game *environment.Game = position.Game()
+	var player environment.Stone = game.Board.CurrentPlayer
+	var playouts int = max(1, backend.Playouts)
+	var area int = position.Height * position.Width
+	var random_agent agents.Agent = agents.NewRandomAgent()
+	var value, score float64 = 0, 0
+	var ownership []float64 = make([]float64, area)
+	for playout := 0; playout < playouts; playout++ {
+		var playout_game *environment.Game = game.DeepCopy()
+		for !playout_game.IsTerminal() && len(playout_game.MoveHistory) < 4*area {
+			playout_game.PlayAction(random_agent.SelectAction(playout_game))
+		}
+		value += playout_game.GameResult(player)
+		var final_score environment.Score = playout_game.ComputeScore()
+		if player == environment.Black {
+			score += final_score.Black - final_score.White
+		} else {
+			score += final_score.White - final_score.Black
+		}
+		for i := 0; i < position.Height; i++ {
+			for j := 0; j < position.Width; j++ {
+				switch playout_game.Board.Matrix[i][j] {
+				case player:
+					ownership[i*position.Width+j] += 1
+				case player.Opponent():
+					ownership[i*position.Width+j] -= 1
+				}
+			}
+		}
+	}
+
+	var mean_score float32 = float32(score / float64(playouts))
+	var response *remote_trainer.EvaluatePositionResponse = &remote_trainer.EvaluatePositionResponse{
+		Policy: position.MaskedPolicy(make([]float64, len(position.LegalMask))),
+		Value:  float32(value / float64(playouts)),
+		Score:  &mean_score,
+	}
+	if want_ownership {
+		response.Ownership = make([]float32, area)
+		for point := range ownership {
+			response.Ownership[point] = float32(ownership[point] / float64(playouts))
+		}
+	}
+	return response
+}
+
+// Influence backend: every stone radiates an influence decreasing with the distance. The points are owned by
+// the side with the most influence, the value follows the resulting score and the policy favors contested points.
+type InfluenceBackend struct {
+	Radius      int     // Points further away than Radius (manhattan distance) are not influenced
+	ValueScale  float64 // Score lead, relative to the board area, that gives a value of tanh(1)
+	PassWeight  float64 // Weight of passing, relative to a settled point
+	Temperature float64 // How sharply the policy favors contested points
+}
+
+func NewInfluenceBackend() *InfluenceBackend {
+	return &InfluenceBackend{
+		Radius:      4,
+		ValueScale:  0.15,
+		PassWeight:  0.05,
+		Temperature: 0.5,
+	}
+}
+
+func (backend *InfluenceBackend) Influence(position *Position) []float64 {
+	// Influence of the player to move on each point, positive when they dominate it
+	var influence []float64 = make([]float64, position.Height*position.Width)
+	for point := range influence {
+		var sign float64
+		switch {
+		case position.Own[point]:
+			sign = 1
+		case position.Opponent[point]:
+			sign = -1
+		default:
+			continue
+		}
+		var si, sj int = point / position.Width, point % position.Width
+		for i := max(0, si-backend.Radius); i <= min(position.Height-1, si+backend.Radius); i++ {
+			for j := max(0, sj-backend.Radius); j <= min(position.Width-1, sj+backend.Radius); j++ {
+				var distance int = abs(i-si) + abs(j-sj)
+				if distance <= backend.Radius {
+					influence[i*position.Width+j] += sign / float64(1+distance*distance)
+				}
+			}
+		}
+	}
+	return influence
+}
+
+func (backend *InfluenceBackend) Evaluate(position *Position, want_ownership bool) *remote_trainer.EvaluatePositionResponse {
+	var influence []float64 = backend.Influence(position)
+	var area int = len(influence)
+	var score float64 = position.Komi
+	var weights []float64 = make([]float64, len(position.LegalMask))
+	for point, point_influence := range influence {
+		score += math.Tanh(2 * point_influence)
+		// Contested points (influence close to 0) are the interesting moves
+		weights[point] = math.Exp(-math.Abs(point_influence) / backend.Temperature)
+	}
+	weights[area] = backend.PassWeight
+
+	var value float32 = float32(math.Tanh(score / (backend.ValueScale * float64(area))))
+	var score_lead float32 = float32(score)
+	var response *remote_trainer.EvaluatePositionResponse = &remote_trainer.EvaluatePositionResponse{
+		Policy: position.MaskedPolicy(weights),
+		Value:  value,
+		Score:  &score_lead,
+	}
+	if want_ownership {
+		response.Ownership = make([]float32, area)
+		for point, point_influence := range influence {
+			response.Ownership[point] = float32(math.Tanh(2 * point_influence))
+		}
+	}
+	return response
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
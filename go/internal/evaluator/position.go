@@ -0,0 +1,101 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/features"
+)
+
+// Position decoded from the feature planes of a request, in the frame of the symmetric planes
+type Position struct {
+	Height      int
+	Width       int
+	Own         []bool // Stones of the player to move, indexed by point
+	Opponent    []bool
+	LegalMask   []bool // height x width + 1, pass last
+	BlackToMove bool
+	Komi        float64 // Komi of the player to move, negative for black
+}
+
+func DecodePosition(request *remote_trainer.EvaluatePositionRequest) (*Position, error) {
+	var height, width int = int(request.Height), int(request.Width)
+	var area int = height * width
+	if height <= 0 || width <= 0 {
+		return nil, fmt.Errorf("invalid board size %dx%d", height, width)
+	}
+	if request.NumPlanes != features.NumPlanes || len(request.Planes) != features.NumPlanes*area {
+		return nil, fmt.Errorf("expected %d planes of %dx%d, got %d values for %d planes", features.NumPlanes, height, width, len(request.Planes), request.NumPlanes)
+	}
+	if len(request.LegalMask) != features.PolicySize(height, width) {
+		return nil, fmt.Errorf("expected a legal mask of %d values, got %d", features.PolicySize(height, width), len(request.LegalMask))
+	}
+	var position *Position = &Position{
+		Height:      height,
+		Width:       width,
+		Own:         make([]bool, area),
+		Opponent:    make([]bool, area),
+		LegalMask:   request.LegalMask,
+		BlackToMove: request.Planes[features.BlackToMovePlane*area] > 0.5,
+		Komi:        float64(request.Planes[features.KomiPlane*area]) * features.KomiScale,
+	}
+	for point := 0; point < area; point++ {
+		position.Own[point] = request.Planes[features.OwnStonesPlane*area+point] > 0.5
+		position.Opponent[point] = request.Planes[features.OpponentStonesPlane*area+point] > 0.5
+	}
+	return position, nil
+}
+
+func (position *Position) Game() *environment.Game {
+	// Game starting from the position, without its history (superko only applies from here)
+	var player, opponent environment.Stone = environment.White, environment.Black
+	if position.BlackToMove {
+		player, opponent = environment.Black, environment.White
+	}
+	var matrix [][]environment.Stone = make([][]environment.Stone, position.Height)
+	for i := range matrix {
+		matrix[i] = make([]environment.Stone, position.Width)
+		for j := range matrix[i] {
+			switch {
+			case position.Own[i*position.Width+j]:
+				matrix[i][j] = player
+			case position.Opponent[i*position.Width+j]:
+				matrix[i][j] = opponent
+			}
+		}
+	}
+	// The komi of the game is the one of white
+	var komi float64 = position.Komi
+	if position.BlackToMove {
+		komi = -komi
+	}
+	return environment.NewGameFromPosition(matrix, player, komi)
+}
+
+func (position *Position) MaskedPolicy(weights []float64) []float32 {
+	// Normalizes the weights of the legal moves, uniform if they are all zero
+	var policy []float32 = make([]float32, len(position.LegalMask))
+	var total float64 = 0
+	for idx, is_legal := range position.LegalMask {
+		if is_legal && weights[idx] > 0 {
+			total += weights[idx]
+		}
+	}
+	var legal_count int = 0
+	for _, is_legal := range position.LegalMask {
+		if is_legal {
+			legal_count++
+		}
+	}
+	for idx, is_legal := range position.LegalMask {
+		switch {
+		case !is_legal:
+		case total > 0:
+			policy[idx] = float32(max(0, weights[idx]) / total)
+		default:
+			policy[idx] = 1 / float32(legal_count)
+		}
+	}
+	return policy
+}
@@ -0,0 +1,103 @@
+package evaluator
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// Server is a stand-in for the network evaluation server, backed by a heuristic
+type Server struct {
+	remote_trainer.UnimplementedPositionEvaluatorServer
+	Backend Backend
+}
+
+// Constructor
+func NewServer(backend Backend) *Server {
+	return &Server{
+		Backend: backend,
+	}
+}
+
+// Methods
+func (server *Server) EvaluatePosition(ctx context.Context, request *remote_trainer.EvaluatePositionRequest) (*remote_trainer.EvaluatePositionResponse, error) {
+	position, err := DecodePosition(request)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return server.Backend.Evaluate(position, request.WantOwnership), nil
+}
+
+func (server *Server) EvaluatePositionBatch(ctx context.Context, request *remote_trainer.EvaluatePositionBatchRequest) (*remote_trainer.EvaluatePositionBatchResponse, error) {
+	// The positions of a batch are evaluated in parallel, like a network would on a batch
+	var response *remote_trainer.EvaluatePositionBatchResponse = &remote_trainer.EvaluatePositionBatchResponse{
+		Evaluations: make([]*remote_trainer.EvaluatePositionResponse, len(request.Positions)),
+	}
+	var errs []error = make([]error, len(request.Positions))
+	var wg sync.WaitGroup
+	wg.Add(len(request.Positions))
+	for position_idx, position_request := range request.Positions {
+		go func() {
+			defer wg.Done()
+			response.Evaluations[position_idx], errs[position_idx] = server.EvaluatePosition(ctx, position_request)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// NewInProcessClient serves the backend over an in-memory connection, for tests and for running without an evaluation server
+func NewInProcessClient(backend Backend) (remote_trainer.PositionEvaluatorClient, func(), error) {
+	var listener *bufconn.Listener = bufconn.Listen(1 << 20)
+	var server *grpc.Server = grpc.NewServer()
+	remote_trainer.RegisterPositionEvaluatorServer(server, NewServer(backend))
+	go server.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		server.Stop()
+		return nil, nil, err
+	}
+	var closer func() = func() {
+		conn.Close()
+		server.Stop()
+	}
+	return remote_trainer.NewPositionEvaluatorClient(conn), closer, nil
+}
+
+// Dial connects to the evaluation server at the address, or serves the fallback backend in process if nothing answers there
+func Dial(address string, fallback Backend) (remote_trainer.PositionEvaluatorClient, func(), error) {
+	var network, target string = "tcp", address
+	if path, ok := strings.CutPrefix(address, "unix://"); ok {
+		network, target = "unix", path
+	}
+	probe, err := net.DialTimeout(network, target, time.Second)
+	if err != nil {
+		println("No position evaluation server at", address+", using the in-process stand-in")
+		return NewInProcessClient(fallback)
+	}
+	probe.Close()
+
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, err
+	}
+	return remote_trainer.NewPositionEvaluatorClient(conn), func() { conn.Close() }, nil
+}
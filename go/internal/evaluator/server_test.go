@@ -0,0 +1,179 @@
+package evaluator
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"google.golang.org/grpc"
+)
+
+// Integration tests of the PUCT search against the evaluation server, served in process over bufconn
+
+const CenterWeight float64 = 9 // Policy weight of the center point, the other legal stones weigh 1
+const BlackValue float32 = 0.3 // Value of every position for black
+
+// Backend with known outputs: the center is preferred, which every symmetry keeps on odd boards, and black leads
+type CenterBackend struct {
+	Positions atomic.Int64
+}
+
+func (backend *CenterBackend) Evaluate(position *Position, want_ownership bool) *remote_trainer.EvaluatePositionResponse {
+	backend.Positions.Add(1)
+	var weights []float64 = make([]float64, len(position.LegalMask))
+	for point := 0; point < position.Height*position.Width; point++ {
+		weights[point] = 1
+	}
+	weights[(position.Height/2)*position.Width+position.Width/2] = CenterWeight
+	var value float32 = BlackValue
+	if !position.BlackToMove {
+		value = -value
+	}
+	return &remote_trainer.EvaluatePositionResponse{
+		Policy: position.MaskedPolicy(weights),
+		Value:  value,
+	}
+}
+
+// Client recording the size of the batches sent to the server
+type CountingClient struct {
+	remote_trainer.PositionEvaluatorClient
+	Mutex   sync.Mutex
+	Batches []int
+}
+
+func (client *CountingClient) EvaluatePositionBatch(ctx context.Context, request *remote_trainer.EvaluatePositionBatchRequest, opts ...grpc.CallOption) (*remote_trainer.EvaluatePositionBatchResponse, error) {
+	client.Mutex.Lock()
+	client.Batches = append(client.Batches, len(request.Positions))
+	client.Mutex.Unlock()
+	return client.PositionEvaluatorClient.EvaluatePositionBatch(ctx, request, opts...)
+}
+
+func NewTestAgent(t *testing.T, backend Backend, simulations int, routines int) (*agents.MctsAgent, *CountingClient) {
+	inner, closer, err := NewInProcessClient(backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(closer)
+	var client *CountingClient = &CountingClient{PositionEvaluatorClient: inner}
+	var agent *agents.MctsAgent = agents.NewPuctAgent(simulations, routines, -1, client)
+	t.Cleanup(agent.Close)
+	return agent, client
+}
+
+func TestPuctPriorsAndValues(t *testing.T) {
+	var backend *CenterBackend = &CenterBackend{}
+	agent, _ := NewTestAgent(t, backend, 64, 4)
+	var game *environment.Game = environment.NewGame(5, 5, 0.5)
+	agent.SelectAction(game.DeepCopy())
+
+	// Priors of the root: the backend policy over the legal moves, resigning excluded
+	var priors []float64 = agent.Root.(*agents.PuctNode).GetPriorsSnapshot()
+	var total float64 = 0
+	for action_idx, action := range game.LegalActions {
+		total += priors[action_idx]
+		var expected float64 = 1 / (CenterWeight + 24)
+		switch action {
+		case environment.PutStone{I: 2, J: 2}:
+			expected = CenterWeight / (CenterWeight + 24)
+		case environment.Resign{}, environment.Pass{}:
+			expected = 0
+		}
+		if math.Abs(priors[action_idx]-expected) > 1e-5 {
+			t.Errorf("prior of %v: got %f, want %f", action, priors[action_idx], expected)
+		}
+	}
+	if math.Abs(total-1) > 1e-5 {
+		t.Errorf("root priors sum to %f", total)
+	}
+
+	// Values: every position is worth BlackValue for black, so is every searched move
+	var info agents.SearchInfo = agent.LastSearchInfo
+	if info.Simulations != 64 {
+		t.Errorf("got %d simulations, want 64", info.Simulations)
+	}
+	if best := info.Candidates[0]; best.Action != (environment.PutStone{I: 2, J: 2}) {
+		t.Errorf("most visited move is %v, want the center", best.Action)
+	}
+	for _, candidate := range info.Candidates {
+		if candidate.Visits > 0 && math.Abs(candidate.Value-float64(BlackValue)) > 1e-4 {
+			t.Errorf("value of %v: got %f, want %f", candidate.Action, candidate.Value, BlackValue)
+		}
+	}
+}
+
+func TestPuctBatching(t *testing.T) {
+	// With a long timeout, the batches only leave full, each routine waits for one position at a time
+	const routines int = 4
+	var backend *CenterBackend = &CenterBackend{}
+	agent, client := NewTestAgent(t, backend, 200, routines)
+	agent.Expander.(*agents.PuctExpander).SetBatching(routines, 50*time.Millisecond)
+	agent.SelectAction(environment.NewGame(9, 9, 6.5))
+
+	client.Mutex.Lock()
+	defer client.Mutex.Unlock()
+	var positions, largest int = 0, 0
+	for _, batch_size := range client.Batches {
+		positions += batch_size
+		largest = max(largest, batch_size)
+	}
+	if positions != int(backend.Positions.Load()) {
+		t.Errorf("%d positions sent in batches, the backend evaluated %d", positions, backend.Positions.Load())
+	}
+	if largest != routines {
+		t.Errorf("largest batch has %d positions, want %d", largest, routines)
+	}
+	if len(client.Batches) >= positions {
+		t.Errorf("%d batches for %d positions, the positions were not batched", len(client.Batches), positions)
+	}
+}
+
+func TestPuctClose(t *testing.T) {
+	// Closing the agent stops its batching goroutine, which then refuses the positions
+	agent, _ := NewTestAgent(t, &CenterBackend{}, 16, 2)
+	agent.SelectAction(environment.NewGame(5, 5, 0.5))
+	agent.Close()
+	var expander *agents.PuctExpander = agent.Expander.(*agents.PuctExpander)
+	request, _ := expander.NewRequest(environment.NewGame(5, 5, 0.5))
+	if _, err := expander.Evaluator.Evaluate(request); err == nil {
+		t.Error("a closed agent still evaluates positions")
+	}
+}
+
+func TestPuctBackends(t *testing.T) {
+	// Every backend drives a search to legal moves with normalized priors, under random symmetries
+	for _, name := range []string{"uniform", "playout", "influence"} {
+		for _, size := range []int{5, 9} {
+			backend, err := NewBackend(name, 4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			agent, _ := NewTestAgent(t, backend, 32, 4)
+			var game *environment.Game = environment.NewGame(size, size, 6.5)
+			for move := 0; move < 4 && !game.IsTerminal(); move++ {
+				var action environment.Action = agent.SelectAction(game.DeepCopy())
+				var total float64 = 0
+				for _, prior := range agent.Root.(*agents.PuctNode).GetPriorsSnapshot() {
+					total += prior
+				}
+				if math.Abs(total-1) > 1e-3 {
+					t.Errorf("%s %dx%d move %d: root priors sum to %f", name, size, size, move, total)
+				}
+				var is_legal bool = false
+				for _, legal_action := range game.LegalActions {
+					is_legal = is_legal || legal_action == action
+				}
+				if !is_legal {
+					t.Fatalf("%s %dx%d move %d: illegal action %v", name, size, size, move, action)
+				}
+				game.PlayAction(action)
+			}
+		}
+	}
+}
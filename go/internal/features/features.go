@@ -0,0 +1,171 @@
+package features
+
+import (
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+)
+
+// Input planes of the network, each one is height x width, from the point of view of the player to move
+const (
+	OwnStonesPlane = iota
+	OpponentStonesPlane
+	EmptyPlane
+	LegalPlane
+	OwnAtariPlane      // Own stones of the groups with a single liberty
+	OpponentAtariPlane // Opponent stones of the groups with a single liberty
+	LastMovePlane
+	BlackToMovePlane // All ones if black is to move
+	KomiPlane        // Komi of the player to move divided by KomiScale
+	NumPlanes
+)
+
+const KomiScale = 15.0
+
+func PolicySize(height, width int) int {
+	// One entry per point and one for passing
+	return height*width + 1
+}
+
+func PolicyIndex(action environment.Action, height, width int) int {
+	// Index of the action in the policy, -1 if the network does not predict it
+	switch typed_action := action.(type) {
+	case environment.PutStone:
+		return typed_action.I*width + typed_action.J
+	case environment.Pass:
+		return height * width
+	default:
+		return -1
+	}
+}
+
+func CountLiberties(game *environment.Game, i, j int, visited [][]bool) (int, []environment.Position) {
+	// Liberties and stones of the group at (i, j), found with a flood fill
+	var stone environment.Stone = game.Board.Matrix[i][j]
+	var stones []environment.Position = []environment.Position{environment.NewPosition(i, j)}
+	var liberties map[environment.Position]bool = make(map[environment.Position]bool)
+	visited[i][j] = true
+	for next := 0; next < len(stones); next++ {
+		for neighbor, neighbor_stone := range game.Board.GetNeighbors(stones[next].First, stones[next].Second) {
+			switch {
+			case neighbor_stone == environment.Empty:
+				liberties[neighbor] = true
+			case neighbor_stone == stone && !visited[neighbor.First][neighbor.Second]:
+				visited[neighbor.First][neighbor.Second] = true
+				stones = append(stones, neighbor)
+			}
+		}
+	}
+	return len(liberties), stones
+}
+
+func Encode(game *environment.Game) []float32 {
+	var height, width int = game.Board.Height, game.Board.Width
+	var area int = height * width
+	var planes []float32 = make([]float32, NumPlanes*area)
+	var player environment.Stone = game.Board.CurrentPlayer
+
+	var visited [][]bool = make([][]bool, height)
+	for i := range visited {
+		visited[i] = make([]bool, width)
+	}
+	for i := 0; i < height; i++ {
+		for j := 0; j < width; j++ {
+			var point int = i*width + j
+			switch game.Board.Matrix[i][j] {
+			case environment.Empty:
+				planes[EmptyPlane*area+point] = 1
+				continue
+			case player:
+				planes[OwnStonesPlane*area+point] = 1
+			default:
+				planes[OpponentStonesPlane*area+point] = 1
+			}
+			if visited[i][j] {
+				continue
+			}
+			var liberties int
+			var stones []environment.Position
+			liberties, stones = CountLiberties(game, i, j, visited)
+			if liberties != 1 {
+				continue
+			}
+			var plane int = OpponentAtariPlane
+			if game.Board.Matrix[i][j] == player {
+				plane = OwnAtariPlane
+			}
+			for _, position := range stones {
+				planes[plane*area+position.First*width+position.Second] = 1
+			}
+		}
+	}
+
+	for _, action := range game.LegalActions {
+		if put_stone, ok := action.(environment.PutStone); ok {
+			planes[LegalPlane*area+put_stone.I*width+put_stone.J] = 1
+		}
+	}
+	if len(game.MoveHistory) > 0 {
+		if put_stone, ok := game.MoveHistory[len(game.MoveHistory)-1].(environment.PutStone); ok {
+			planes[LastMovePlane*area+put_stone.I*width+put_stone.J] = 1
+		}
+	}
+	var komi float32 = float32(game.Komi / KomiScale)
+	if player == environment.Black {
+		komi = -komi
+	}
+	for point := 0; point < area; point++ {
+		if player == environment.Black {
+			planes[BlackToMovePlane*area+point] = 1
+		}
+		planes[KomiPlane*area+point] = komi
+	}
+	return planes
+}
+
+func LegalMask(game *environment.Game) []bool {
+	var mask []bool = make([]bool, PolicySize(game.Board.Height, game.Board.Width))
+	for _, action := range game.LegalActions {
+		if policy_idx := PolicyIndex(action, game.Board.Height, game.Board.Width); policy_idx != -1 {
+			mask[policy_idx] = true
+		}
+	}
+	return mask
+}
+
+func Priors(game *environment.Game, policy []float32) []float64 {
+	// Probabilities of the legal actions of the game, renormalized over the moves the network predicts.
+	// Resigning gets no prior, the search only resigns when nothing else is worth playing.
+	var height, width int = game.Board.Height, game.Board.Width
+	var priors []float64 = make([]float64, len(game.LegalActions))
+	var total float64 = 0
+	if len(policy) == PolicySize(height, width) {
+		for action_idx, action := range game.LegalActions {
+			if policy_idx := PolicyIndex(action, height, width); policy_idx != -1 {
+				priors[action_idx] = max(0, float64(policy[policy_idx]))
+				total += priors[action_idx]
+			}
+		}
+	}
+	if total == 0 {
+		return UniformPriors(game)
+	}
+	for action_idx := range priors {
+		priors[action_idx] /= total
+	}
+	return priors
+}
+
+func UniformPriors(game *environment.Game) []float64 {
+	var priors []float64 = make([]float64, len(game.LegalActions))
+	var count int = 0
+	for _, action := range game.LegalActions {
+		if PolicyIndex(action, game.Board.Height, game.Board.Width) != -1 {
+			count++
+		}
+	}
+	for action_idx, action := range game.LegalActions {
+		if PolicyIndex(action, game.Board.Height, game.Board.Width) != -1 {
+			priors[action_idx] = 1 / float64(count)
+		}
+	}
+	return priors
+}
@@ -0,0 +1,80 @@
+package features
+
+// The 8 symmetries of a square board: bit 0 flips the rows, bit 1 flips the columns, bit 2 transposes.
+// Rectangular boards only have the first 4.
+type Symmetry int
+
+const NumSymmetries = 8
+
+func CountSymmetries(height, width int) int {
+	if height == width {
+		return NumSymmetries
+	}
+	return NumSymmetries / 2
+}
+
+func (symmetry Symmetry) Apply(i, j, height, width int) (int, int) {
+	// Coordinates of the point (i, j) once the symmetry is applied, the board stays height x width
+	if symmetry&1 != 0 {
+		i = height - 1 - i
+	}
+	if symmetry&2 != 0 {
+		j = width - 1 - j
+	}
+	if symmetry&4 != 0 {
+		i, j = j, i
+	}
+	return i, j
+}
+
+func (symmetry Symmetry) TransformPlanes(planes []float32, num_planes, height, width int) []float32 {
+	var area int = height * width
+	var transformed []float32 = make([]float32, len(planes))
+	for i := 0; i < height; i++ {
+		for j := 0; j < width; j++ {
+			var ti, tj int = symmetry.Apply(i, j, height, width)
+			for plane := 0; plane < num_planes; plane++ {
+				transformed[plane*area+ti*width+tj] = planes[plane*area+i*width+j]
+			}
+		}
+	}
+	return transformed
+}
+
+func (symmetry Symmetry) TransformMask(mask []bool, height, width int) []bool {
+	// The pass flag stays last
+	var transformed []bool = make([]bool, len(mask))
+	for i := 0; i < height; i++ {
+		for j := 0; j < width; j++ {
+			var ti, tj int = symmetry.Apply(i, j, height, width)
+			transformed[ti*width+tj] = mask[i*width+j]
+		}
+	}
+	for idx := height * width; idx < len(mask); idx++ {
+		transformed[idx] = mask[idx]
+	}
+	return transformed
+}
+
+func (symmetry Symmetry) InvertPolicy(policy []float32, height, width int) []float32 {
+	// Brings a policy predicted on the symmetric board back to the original board, the pass entry is unchanged
+	var original []float32 = make([]float32, len(policy))
+	if len(policy) < height*width {
+		return original
+	}
+	for i := 0; i < height; i++ {
+		for j := 0; j < width; j++ {
+			var ti, tj int = symmetry.Apply(i, j, height, width)
+			original[i*width+j] = policy[ti*width+tj]
+		}
+	}
+	for idx := height * width; idx < len(policy); idx++ {
+		original[idx] = policy[idx]
+	}
+	return original
+}
+
+func (symmetry Symmetry) InvertPoints(values []float32, height, width int) []float32 {
+	// Same as InvertPolicy for per-point outputs like ownership
+	return symmetry.InvertPolicy(values, height, width)
+}
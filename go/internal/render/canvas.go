@@ -0,0 +1,149 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+type Align int
+
+const (
+	AlignStart Align = iota
+	AlignCenter
+)
+
+// Antialiased shapes and text on an image, the counterpart of the ebiten vector and text packages
+type Canvas struct {
+	Image      *image.RGBA
+	Face       font.Face
+	Rasterizer *vector.Rasterizer
+}
+
+// Constructor
+func NewCanvas(width, height int, font_size float64) (*Canvas, error) {
+	parsed_font, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return nil, err
+	}
+	face, err := opentype.NewFace(parsed_font, &opentype.FaceOptions{Size: font_size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+	return &Canvas{
+		Image:      image.NewRGBA(image.Rect(0, 0, width, height)),
+		Face:       face,
+		Rasterizer: vector.NewRasterizer(0, 0),
+	}, nil
+}
+
+// Methods
+func (canvas *Canvas) Fill(fill_color color.Color) {
+	draw.Draw(canvas.Image, canvas.Image.Bounds(), image.NewUniform(fill_color), image.Point{}, draw.Src)
+}
+
+func (canvas *Canvas) FillPath(x0, y0, x1, y1 float32, fill_color color.Color, path func(rasterizer *vector.Rasterizer, dx, dy float32)) {
+	// The rasterizer only covers the bounding box of the path, path draws with coordinates shifted by (dx, dy)
+	var bounds image.Rectangle = image.Rect(int(math.Floor(float64(x0))), int(math.Floor(float64(y0))), int(math.Ceil(float64(x1))), int(math.Ceil(float64(y1))))
+	bounds = bounds.Intersect(canvas.Image.Bounds())
+	if bounds.Empty() {
+		return
+	}
+	canvas.Rasterizer.Reset(bounds.Dx(), bounds.Dy())
+	canvas.Rasterizer.DrawOp = draw.Over
+	path(canvas.Rasterizer, -float32(bounds.Min.X), -float32(bounds.Min.Y))
+	canvas.Rasterizer.Draw(canvas.Image, bounds, image.NewUniform(fill_color), image.Point{})
+}
+
+func Circle(rasterizer *vector.Rasterizer, cx, cy, radius float32, clockwise bool) {
+	// Four cubic Bezier arcs, the direction matters to cut holes
+	const kappa float32 = 0.5522847
+	var k float32 = kappa * radius
+	var sign float32 = 1
+	if !clockwise {
+		sign = -1
+	}
+	rasterizer.MoveTo(cx+radius, cy)
+	rasterizer.CubeTo(cx+radius, cy+sign*k, cx+k, cy+sign*radius, cx, cy+sign*radius)
+	rasterizer.CubeTo(cx-k, cy+sign*radius, cx-radius, cy+sign*k, cx-radius, cy)
+	rasterizer.CubeTo(cx-radius, cy-sign*k, cx-k, cy-sign*radius, cx, cy-sign*radius)
+	rasterizer.CubeTo(cx+k, cy-sign*radius, cx+radius, cy-sign*k, cx+radius, cy)
+	rasterizer.ClosePath()
+}
+
+func (canvas *Canvas) FillCircle(cx, cy, radius float32, fill_color color.Color) {
+	canvas.FillPath(cx-radius, cy-radius, cx+radius, cy+radius, fill_color, func(rasterizer *vector.Rasterizer, dx, dy float32) {
+		Circle(rasterizer, cx+dx, cy+dy, radius, true)
+	})
+}
+
+func (canvas *Canvas) StrokeCircle(cx, cy, radius, stroke_width float32, stroke_color color.Color) {
+	var outer, inner float32 = radius + stroke_width/2, max(0, radius-stroke_width/2)
+	canvas.FillPath(cx-outer, cy-outer, cx+outer, cy+outer, stroke_color, func(rasterizer *vector.Rasterizer, dx, dy float32) {
+		Circle(rasterizer, cx+dx, cy+dy, outer, true)
+		Circle(rasterizer, cx+dx, cy+dy, inner, false)
+	})
+}
+
+func (canvas *Canvas) FillRect(x, y, width, height float32, fill_color color.Color) {
+	canvas.FillPath(x, y, x+width, y+height, fill_color, func(rasterizer *vector.Rasterizer, dx, dy float32) {
+		rasterizer.MoveTo(x+dx, y+dy)
+		rasterizer.LineTo(x+width+dx, y+dy)
+		rasterizer.LineTo(x+width+dx, y+height+dy)
+		rasterizer.LineTo(x+dx, y+height+dy)
+		rasterizer.ClosePath()
+	})
+}
+
+func (canvas *Canvas) StrokeRect(x, y, width, height, stroke_width float32, stroke_color color.Color) {
+	var half float32 = stroke_width / 2
+	canvas.FillPath(x-half, y-half, x+width+half, y+height+half, stroke_color, func(rasterizer *vector.Rasterizer, dx, dy float32) {
+		rasterizer.MoveTo(x-half+dx, y-half+dy)
+		rasterizer.LineTo(x+width+half+dx, y-half+dy)
+		rasterizer.LineTo(x+width+half+dx, y+height+half+dy)
+		rasterizer.LineTo(x-half+dx, y+height+half+dy)
+		rasterizer.ClosePath()
+		// Hole in the opposite direction
+		rasterizer.MoveTo(x+half+dx, y+half+dy)
+		rasterizer.LineTo(x+half+dx, y+height-half+dy)
+		rasterizer.LineTo(x+width-half+dx, y+height-half+dy)
+		rasterizer.LineTo(x+width-half+dx, y+half+dy)
+		rasterizer.ClosePath()
+	})
+}
+
+func (canvas *Canvas) StrokeLine(x0, y0, x1, y1, stroke_width float32, stroke_color color.Color) {
+	// Rectangle around the segment
+	var length float32 = float32(math.Hypot(float64(x1-x0), float64(y1-y0)))
+	if length == 0 {
+		return
+	}
+	var nx, ny float32 = -(y1 - y0) / length * stroke_width / 2, (x1 - x0) / length * stroke_width / 2
+	var half float32 = stroke_width / 2
+	canvas.FillPath(min(x0, x1)-half, min(y0, y1)-half, max(x0, x1)+half, max(y0, y1)+half, stroke_color, func(rasterizer *vector.Rasterizer, dx, dy float32) {
+		rasterizer.MoveTo(x0+nx+dx, y0+ny+dy)
+		rasterizer.LineTo(x1+nx+dx, y1+ny+dy)
+		rasterizer.LineTo(x1-nx+dx, y1-ny+dy)
+		rasterizer.LineTo(x0-nx+dx, y0-ny+dy)
+		rasterizer.ClosePath()
+	})
+}
+
+func (canvas *Canvas) DrawText(message string, x, y float32, align Align, text_color color.Color) {
+	// Vertically centered on y, like the text of the window
+	var drawer *font.Drawer = &font.Drawer{Dst: canvas.Image, Src: image.NewUniform(text_color), Face: canvas.Face}
+	var metrics font.Metrics = canvas.Face.Metrics()
+	var start fixed.Int26_6 = fixed.Int26_6(x * 64)
+	if align == AlignCenter {
+		start -= drawer.MeasureString(message) / 2
+	}
+	drawer.Dot = fixed.Point26_6{X: start, Y: fixed.Int26_6(y*64) + (metrics.CapHeight)/2}
+	drawer.DrawString(message)
+}
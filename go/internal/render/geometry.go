@@ -0,0 +1,143 @@
+package render
+
+import (
+	"image/color"
+	"strconv"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+)
+
+// Colors shared by the window and the images
+var BoardColor color.RGBA = color.RGBA{200, 170, 120, 255}
+var MarkerColor color.RGBA = color.RGBA{220, 30, 30, 255}
+
+// Markers, relative to the stone radius
+const LastMoveRadiusScale float32 = 0.5
+const NumberedLastMoveRadiusScale float32 = 0.8 // Around the move number
+const KoSquareScale float32 = 0.5               // Half side of the square on the ko points
+
+func StoneColor(stone environment.Stone) color.Color {
+	if stone == environment.White {
+		return color.White
+	}
+	return color.Black
+}
+
+func HeatColor(fraction float32) color.NRGBA {
+	// Shading of a point the search visited, fraction is its share of the visits of the best move
+	return color.NRGBA{30, 60, 200, uint8(30 + 170*max(0, min(1, fraction)))}
+}
+
+// Position of the intersections of a board in pixels
+type Geometry struct {
+	Left             float32 // Top left intersection
+	Top              float32
+	BoardSize        float32 // Side of the square holding the intersections
+	Height           int
+	Width            int
+	StoneRadiusScale float32 // Relative to the cell size
+	StarRadiusScale  float32
+}
+
+// Constructor
+func NewGeometry(left, top, board_size float32, height, width int, stone_radius_scale, star_radius_scale float32) Geometry {
+	return Geometry{
+		Left:             left,
+		Top:              top,
+		BoardSize:        board_size,
+		Height:           height,
+		Width:            width,
+		StoneRadiusScale: stone_radius_scale,
+		StarRadiusScale:  star_radius_scale,
+	}
+}
+
+// Methods
+func (geometry Geometry) CellSize() float32 {
+	// The cells are square, the spacing is the one of the side with the most lines
+	return min(geometry.BoardSize/float32(geometry.Width-1), geometry.BoardSize/float32(geometry.Height-1))
+}
+
+func (geometry Geometry) GridWidth() float32 {
+	return geometry.CellSize() * float32(geometry.Width-1)
+}
+
+func (geometry Geometry) GridHeight() float32 {
+	return geometry.CellSize() * float32(geometry.Height-1)
+}
+
+func (geometry Geometry) Point(i, j int) (float32, float32) {
+	// Center of the intersection of row i and column j
+	return geometry.Left + geometry.CellSize()*float32(j), geometry.Top + geometry.CellSize()*float32(i)
+}
+
+func (geometry Geometry) StoneRadius() float32 {
+	return geometry.CellSize() * geometry.StoneRadiusScale
+}
+
+func (geometry Geometry) StarRadius() float32 {
+	return geometry.CellSize() * geometry.StarRadiusScale
+}
+
+func StarLines(size int) []int {
+	// Lines of the star points along a side: those of the handicap stones, only the center line below 7
+	if size < 7 {
+		if size%2 == 1 {
+			return []int{size / 2}
+		}
+		return nil
+	}
+	var edge int = 2
+	if size >= 13 {
+		edge = 3
+	}
+	if size == 7 || size%2 == 0 {
+		return []int{edge, size - 1 - edge}
+	}
+	return []int{edge, size / 2, size - 1 - edge}
+}
+
+func (geometry Geometry) StarPoints() []environment.Position {
+	// Where the handicap stones go on square boards, each side has its own lines on rectangular ones
+	var points []environment.Position
+	for _, i := range StarLines(geometry.Height) {
+		for _, j := range StarLines(geometry.Width) {
+			points = append(points, environment.NewPosition(i, j))
+		}
+	}
+	return points
+}
+
+func (geometry Geometry) IntersectionAt(x, y float32) (environment.Position, bool) {
+	// Closest intersection of the pixel, if it is less than half a cell away
+	var j float32 = (x - geometry.Left) / geometry.CellSize()
+	var i float32 = (y - geometry.Top) / geometry.CellSize()
+	var rounded_i, rounded_j int = int(i + 0.5), int(j + 0.5)
+	if i < -0.5 || j < -0.5 || rounded_i >= geometry.Height || rounded_j >= geometry.Width {
+		return environment.Position{}, false
+	}
+	return environment.NewPosition(rounded_i, rounded_j), true
+}
+
+func (geometry Geometry) ColumnLabel(j int) string {
+	// GTP letters, without I
+	const columns string = "ABCDEFGHJKLMNOPQRSTUVWXYZ"
+	return string(columns[j])
+}
+
+func (geometry Geometry) RowLabel(i int) string {
+	// Row 1 is at the bottom
+	return strconv.Itoa(geometry.Height - i)
+}
+
+func LastStone(game *environment.Game, first_move int) (environment.PutStone, bool) {
+	// Last stone played after the first moves, if it is still on the board
+	if len(game.MoveHistory) <= first_move {
+		return environment.PutStone{}, false
+	}
+	stone, ok := game.MoveHistory[len(game.MoveHistory)-1].(environment.PutStone)
+	if !ok || game.Board.Matrix[stone.I][stone.J] == environment.Empty {
+		return environment.PutStone{}, false
+	}
+	return stone, true
+}
@@ -0,0 +1,215 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"strconv"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+)
+
+// Sizes relative to the cell size, the window uses the same ones
+const StoneRadiusScale float32 = 0.4
+const StarRadiusScale float32 = 0.1
+
+type Options struct {
+	Size        int // Width and height of the image in pixels
+	Coordinates bool
+	LastMove    bool
+	KoPoints    bool
+	MoveNumbers bool
+	FirstMove   int         // Moves before it are the setup, they are neither numbered nor marked
+	Heatmap     [][]float64 // Shading of each point in [0, 1], nil for none
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Size:        512,
+		Coordinates: true,
+		LastMove:    true,
+		KoPoints:    true,
+	}
+}
+
+func (options Options) Geometry(board *environment.Board) Geometry {
+	// The board fills the image, with room for the coordinates around it
+	var size float32 = float32(options.Size)
+	var cells float32 = float32(max(board.Height, board.Width) - 1)
+	var margin float32 = 0.6 * size / (cells + 1.2)
+	if options.Coordinates {
+		margin = size / (cells + 2)
+	}
+	return NewGeometry(margin, margin, size-2*margin, board.Height, board.Width, StoneRadiusScale, StarRadiusScale)
+}
+
+func Render(game *environment.Game, options Options) (*image.RGBA, error) {
+	var geometry Geometry = options.Geometry(game.Board)
+	var pixel float32 = max(1, float32(options.Size)/400) // A pixel of the default window
+	canvas, err := NewCanvas(options.Size, options.Size, float64(max(8, 0.35*geometry.CellSize())))
+	if err != nil {
+		return nil, err
+	}
+	canvas.Fill(BoardColor)
+
+	// Grid, star points and coordinates, as in ui.DrawGrid
+	for i := 0; i < geometry.Height; i++ {
+		var x0, y0 float32 = geometry.Point(i, 0)
+		canvas.StrokeLine(x0, y0, x0+geometry.GridWidth(), y0, 2*pixel, color.Black)
+	}
+	for j := 0; j < geometry.Width; j++ {
+		var x0, y0 float32 = geometry.Point(0, j)
+		canvas.StrokeLine(x0, y0, x0, y0+geometry.GridHeight(), 2*pixel, color.Black)
+	}
+	for _, pos := range geometry.StarPoints() {
+		var cx, cy float32 = geometry.Point(pos.First, pos.Second)
+		canvas.FillCircle(cx, cy, geometry.StarRadius(), color.Black)
+	}
+	if options.Coordinates {
+		var offset float32 = 0.6 * geometry.Left
+		for j := 0; j < geometry.Width; j++ {
+			x, _ := geometry.Point(0, j)
+			canvas.DrawText(geometry.ColumnLabel(j), x, geometry.Top-offset, AlignCenter, color.Black)
+			canvas.DrawText(geometry.ColumnLabel(j), x, geometry.Top+geometry.GridHeight()+offset, AlignCenter, color.Black)
+		}
+		for i := 0; i < geometry.Height; i++ {
+			_, y := geometry.Point(i, 0)
+			canvas.DrawText(geometry.RowLabel(i), geometry.Left-offset, y, AlignCenter, color.Black)
+			canvas.DrawText(geometry.RowLabel(i), geometry.Left+geometry.GridWidth()+offset, y, AlignCenter, color.Black)
+		}
+	}
+
+	// Heatmap on the empty points, then the stones, as in ui.DrawStones
+	for i := range options.Heatmap {
+		for j, heat := range options.Heatmap[i] {
+			if heat <= 0 || game.Board.Matrix[i][j] != environment.Empty {
+				continue
+			}
+			var cx, cy float32 = geometry.Point(i, j)
+			canvas.FillCircle(cx, cy, geometry.StoneRadius(), HeatColor(float32(heat)))
+		}
+	}
+	for i := 0; i < geometry.Height; i++ {
+		for j := 0; j < geometry.Width; j++ {
+			var stone environment.Stone = game.Board.Matrix[i][j]
+			if stone == environment.Empty {
+				continue
+			}
+			var cx, cy float32 = geometry.Point(i, j)
+			canvas.FillCircle(cx, cy, geometry.StoneRadius(), StoneColor(stone))
+		}
+	}
+
+	// Markers
+	if options.MoveNumbers {
+		for i, row := range MoveNumbers(game, options.FirstMove) {
+			for j, number := range row {
+				var stone environment.Stone = game.Board.Matrix[i][j]
+				if stone == environment.Empty || number == 0 {
+					continue
+				}
+				var cx, cy float32 = geometry.Point(i, j)
+				canvas.DrawText(strconv.Itoa(number), cx, cy, AlignCenter, StoneColor(stone.Opponent()))
+			}
+		}
+	}
+	if stone, ok := LastStone(game, options.FirstMove); ok && options.LastMove {
+		var cx, cy float32 = geometry.Point(stone.I, stone.J)
+		var marker_color color.Color = StoneColor(game.Board.Matrix[stone.I][stone.J].Opponent())
+		var radius float32 = LastMoveRadiusScale * geometry.StoneRadius()
+		if options.MoveNumbers {
+			marker_color, radius = MarkerColor, NumberedLastMoveRadiusScale*geometry.StoneRadius()
+		}
+		canvas.StrokeCircle(cx, cy, radius, 2*pixel, marker_color)
+	}
+	if options.KoPoints && !game.IsTerminal() {
+		var half_side float32 = KoSquareScale * geometry.StoneRadius()
+		for _, pos := range game.KoPoints() {
+			var cx, cy float32 = geometry.Point(pos.First, pos.Second)
+			canvas.FillRect(cx-half_side, cy-half_side, 2*half_side, 2*half_side, BoardColor)
+			canvas.StrokeRect(cx-half_side, cy-half_side, 2*half_side, 2*half_side, 2*pixel, MarkerColor)
+		}
+	}
+	return canvas.Image, nil
+}
+
+func MoveNumbers(game *environment.Game, first_move int) [][]int {
+	// Number of the move that put each stone on the board, 0 for the stones of the setup
+	var numbers [][]int = make([][]int, game.Board.Height)
+	for i := range numbers {
+		numbers[i] = make([]int, game.Board.Width)
+	}
+	for move_idx, action := range game.MoveHistory[first_move:] {
+		if stone, ok := action.(environment.PutStone); ok {
+			numbers[stone.I][stone.J] = move_idx + 1
+		}
+	}
+	return numbers
+}
+
+func HeatmapFromSearch(info agents.SearchInfo, height, width int) [][]float64 {
+	// Visits of each point relative to the most visited move
+	var heatmap [][]float64 = make([][]float64, height)
+	for i := range heatmap {
+		heatmap[i] = make([]float64, width)
+	}
+	if len(info.Candidates) == 0 || info.Candidates[0].Visits == 0 {
+		return heatmap
+	}
+	for _, candidate := range info.Candidates {
+		if stone, ok := candidate.Action.(environment.PutStone); ok {
+			heatmap[stone.I][stone.J] = float64(candidate.Visits) / float64(info.Candidates[0].Visits)
+		}
+	}
+	return heatmap
+}
+
+func GifPalette() color.Palette {
+	// Gradients between the colors of the board, so that the antialiased edges stay smooth
+	var palette color.Palette
+	var gradient func(from, to color.RGBA, steps int) = func(from, to color.RGBA, steps int) {
+		for step := 0; step < steps; step++ {
+			var t float64 = float64(step) / float64(steps-1)
+			var lerp func(a, b uint8) uint8 = func(a, b uint8) uint8 {
+				return uint8(float64(a) + t*(float64(b)-float64(a)) + 0.5)
+			}
+			palette = append(palette, color.RGBA{lerp(from.R, to.R), lerp(from.G, to.G), lerp(from.B, to.B), 255})
+		}
+	}
+	var black, white color.RGBA = color.RGBA{0, 0, 0, 255}, color.RGBA{255, 255, 255, 255}
+	var heat color.RGBA = color.RGBA{30, 60, 200, 255}
+	gradient(black, white, 64)
+	gradient(BoardColor, black, 48)
+	gradient(BoardColor, white, 48)
+	gradient(BoardColor, MarkerColor, 32)
+	gradient(BoardColor, heat, 32)
+	gradient(heat, white, 16)
+	gradient(MarkerColor, black, 16)
+	return palette
+}
+
+func RenderGif(positions []*environment.Game, options Options, heatmaps [][][]float64, delay int) (*gif.GIF, error) {
+	// One frame per position, delay is in hundredths of a second, heatmaps may be nil
+	var animation *gif.GIF = &gif.GIF{}
+	var palette color.Palette = GifPalette()
+	for position_idx, game := range positions {
+		var frame_options Options = options
+		if heatmaps != nil {
+			frame_options.Heatmap = heatmaps[position_idx]
+		}
+		frame, err := Render(game, frame_options)
+		if err != nil {
+			return nil, err
+		}
+		var paletted *image.Paletted = image.NewPaletted(frame.Bounds(), palette)
+		draw.Draw(paletted, frame.Bounds(), frame, image.Point{}, draw.Src)
+		animation.Image = append(animation.Image, paletted)
+		animation.Delay = append(animation.Delay, delay)
+	}
+	if len(animation.Delay) > 0 {
+		animation.Delay[len(animation.Delay)-1] = 4 * delay // Pause on the final position before looping
+	}
+	return animation, nil
+}
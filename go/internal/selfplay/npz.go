@@ -0,0 +1,76 @@
+package selfplay
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Minimal writer of the numpy formats: an .npz file is a zip archive of .npy arrays, loaded with numpy.load
+
+type NpyArray struct {
+	Name  string
+	Descr string // Numpy type of the elements: <f4, <i4 or |u1
+	Shape []int
+	Data  any // Slice of float32, int32 or uint8, in C order
+}
+
+func NpyHeader(descr string, shape []int) []byte {
+	var dims []string = make([]string, len(shape))
+	for dim_idx, dim := range shape {
+		dims[dim_idx] = fmt.Sprint(dim)
+	}
+	var shape_str string = strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		shape_str += ","
+	}
+	var dict string = fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (%s), }", descr, shape_str)
+
+	// Version 1.0: magic string, version, little-endian header length, then the header padded so that the data is 64-byte aligned
+	var prefix_length int = 6 + 2 + 2
+	var padding int = 64 - (prefix_length+len(dict)+1)%64
+	if padding == 64 {
+		padding = 0
+	}
+	dict += strings.Repeat(" ", padding) + "\n"
+
+	var header []byte = make([]byte, 0, prefix_length+len(dict))
+	header = append(header, "\x93NUMPY"...)
+	header = append(header, 1, 0)
+	header = binary.LittleEndian.AppendUint16(header, uint16(len(dict)))
+	header = append(header, dict...)
+	return header
+}
+
+func WriteNpy(writer io.Writer, array NpyArray) error {
+	if _, err := writer.Write(NpyHeader(array.Descr, array.Shape)); err != nil {
+		return err
+	}
+	return binary.Write(writer, binary.LittleEndian, array.Data)
+}
+
+func WriteNpz(writer io.Writer, arrays []NpyArray, extra_files map[string][]byte) error {
+	// Arrays are deflated, which numpy.load reads like numpy.savez_compressed files
+	var archive *zip.Writer = zip.NewWriter(writer)
+	for _, array := range arrays {
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: array.Name + ".npy", Method: zip.Deflate})
+		if err != nil {
+			return err
+		}
+		if err := WriteNpy(file, array); err != nil {
+			return err
+		}
+	}
+	for name, content := range extra_files {
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
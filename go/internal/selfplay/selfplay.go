@@ -0,0 +1,52 @@
+package selfplay
+
+import (
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/features"
+)
+
+func VisitDistribution(agent *agents.MctsAgent, game *environment.Game) []float32 {
+	// Root visits of the last search as a policy target, resigning is not part of the policy
+	var height, width int = game.Board.Height, game.Board.Width
+	var policy []float32 = make([]float32, features.PolicySize(height, width))
+	var visits []int
+	visits, _ = agent.Root.GetStatsSnapshot()
+	var total int = 0
+	for action_idx, action_visits := range visits {
+		if policy_idx := features.PolicyIndex(game.LegalActions[action_idx], height, width); policy_idx != -1 {
+			policy[policy_idx] = float32(action_visits)
+			total += action_visits
+		}
+	}
+	for policy_idx := range policy {
+		if total > 0 {
+			policy[policy_idx] /= float32(total)
+		}
+	}
+	return policy
+}
+
+// PlayGame plays the agent against itself and returns one example per position, the agent should use a SelfPlayConfig
+func PlayGame(agent *agents.MctsAgent, game_id int, height, width int, komi float64, max_moves int) ([]Example, *environment.Game) {
+	var game *environment.Game = environment.NewGame(height, width, komi)
+	var examples []Example = make([]Example, 0)
+	var players []environment.Stone = make([]environment.Stone, 0)
+	for !game.IsTerminal() && len(game.MoveHistory) < max_moves {
+		var action environment.Action = agent.SelectAction(game.DeepCopy())
+		examples = append(examples, Example{
+			Features:   features.Encode(game),
+			Policy:     VisitDistribution(agent, game),
+			GameId:     game_id,
+			MoveNumber: len(game.MoveHistory),
+		})
+		players = append(players, game.Board.CurrentPlayer)
+		game.PlayAction(action)
+	}
+
+	// Games stopped at max_moves are scored as they are
+	for example_idx := range examples {
+		examples[example_idx].Value = float32(game.GameResult(players[example_idx]))
+	}
+	return examples, game
+}
@@ -0,0 +1,193 @@
+package selfplay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/features"
+)
+
+// Training data shards, written as <prefix>-<index>.npz and loaded with numpy.load. With N positions per shard,
+// P = features.NumPlanes input planes and a height x width board, each shard contains:
+//
+//	features     float32 (N, P, height, width)  input planes, from the point of view of the player to move
+//	policy       float32 (N, height*width + 1)  root visit distribution, pass last, sums to 1
+//	value        float32 (N,)                   final result of the game for the player to move: 1 win, -1 loss
+//	game_id      int32   (N,)                   index of the game in the self-play session
+//	move_number  int32   (N,)                   number of moves played before the position
+//	metadata.json                               ShardMetadata, read with json.loads(shard["metadata.json"])
+//
+// Shards are written to a temporary file and renamed, a trainer watching the directory never reads a partial shard.
+
+type Example struct {
+	Features   []float32
+	Policy     []float32
+	Value      float32
+	GameId     int
+	MoveNumber int
+}
+
+type ShardMetadata struct {
+	FormatVersion int       `json:"format_version"`
+	ModelVersion  string    `json:"model_version"`
+	Komi          float64   `json:"komi"`
+	Height        int       `json:"height"`
+	Width         int       `json:"width"`
+	NumPlanes     int       `json:"num_planes"`
+	Positions     int       `json:"positions"`
+	Games         int       `json:"games"` // Number of distinct games with positions in the shard
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+const FormatVersion = 1
+
+type ShardWriter struct {
+	Mutex        sync.Mutex
+	Dir          string
+	Prefix       string
+	ShardSize    int // Number of positions per shard, the last shard may be smaller
+	ModelVersion string
+	Komi         float64
+	Height       int
+	Width        int
+	Buffer       []Example
+	NextShard    int
+	Written      []string // Paths of the shards written so far
+}
+
+// Constructor
+func NewShardWriter(dir string, prefix string, shard_size int, model_version string, komi float64, height, width int) (*ShardWriter, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &ShardWriter{
+		Dir:          dir,
+		Prefix:       prefix,
+		ShardSize:    max(1, shard_size),
+		ModelVersion: model_version,
+		Komi:         komi,
+		Height:       height,
+		Width:        width,
+		Buffer:       make([]Example, 0, shard_size),
+		NextShard:    0,
+	}, nil
+}
+
+// Methods
+func (writer *ShardWriter) Add(examples []Example) error {
+	writer.Mutex.Lock()
+	defer writer.Mutex.Unlock()
+
+	for _, example := range examples {
+		writer.Buffer = append(writer.Buffer, example)
+		if len(writer.Buffer) >= writer.ShardSize {
+			if err := writer.WriteShard(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (writer *ShardWriter) SetModelVersion(model_version string) error {
+	// The positions of the previous version are written first, every shard comes from a single version
+	writer.Mutex.Lock()
+	defer writer.Mutex.Unlock()
+
+	if model_version == writer.ModelVersion {
+		return nil
+	}
+	if len(writer.Buffer) > 0 {
+		if err := writer.WriteShard(); err != nil {
+			return err
+		}
+	}
+	writer.ModelVersion = model_version
+	return nil
+}
+
+func (writer *ShardWriter) Close() error {
+	// Writes the positions left in the buffer
+	writer.Mutex.Lock()
+	defer writer.Mutex.Unlock()
+
+	if len(writer.Buffer) == 0 {
+		return nil
+	}
+	return writer.WriteShard()
+}
+
+func (writer *ShardWriter) WriteShard() error {
+	// Called with the mutex held
+	var n int = len(writer.Buffer)
+	var area int = writer.Height * writer.Width
+	var policy_size int = features.PolicySize(writer.Height, writer.Width)
+	var planes []float32 = make([]float32, 0, n*features.NumPlanes*area)
+	var policies []float32 = make([]float32, 0, n*policy_size)
+	var values []float32 = make([]float32, n)
+	var game_ids []int32 = make([]int32, n)
+	var move_numbers []int32 = make([]int32, n)
+	var games map[int]bool = make(map[int]bool)
+	for example_idx, example := range writer.Buffer {
+		if len(example.Features) != features.NumPlanes*area || len(example.Policy) != policy_size {
+			return fmt.Errorf("example %d of game %d does not match the %dx%d board", example.MoveNumber, example.GameId, writer.Height, writer.Width)
+		}
+		planes = append(planes, example.Features...)
+		policies = append(policies, example.Policy...)
+		values[example_idx] = example.Value
+		game_ids[example_idx] = int32(example.GameId)
+		move_numbers[example_idx] = int32(example.MoveNumber)
+		games[example.GameId] = true
+	}
+
+	var metadata ShardMetadata = ShardMetadata{
+		FormatVersion: FormatVersion,
+		ModelVersion:  writer.ModelVersion,
+		Komi:          writer.Komi,
+		Height:        writer.Height,
+		Width:         writer.Width,
+		NumPlanes:     features.NumPlanes,
+		Positions:     n,
+		Games:         len(games),
+		CreatedAt:     time.Now().UTC(),
+	}
+	metadata_json, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	var path string = filepath.Join(writer.Dir, fmt.Sprintf("%s-%05d.npz", writer.Prefix, writer.NextShard))
+	file, err := os.CreateTemp(writer.Dir, ".shard-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name()) // No-op once renamed
+	err = WriteNpz(file, []NpyArray{
+		{Name: "features", Descr: "<f4", Shape: []int{n, features.NumPlanes, writer.Height, writer.Width}, Data: planes},
+		{Name: "policy", Descr: "<f4", Shape: []int{n, policy_size}, Data: policies},
+		{Name: "value", Descr: "<f4", Shape: []int{n}, Data: values},
+		{Name: "game_id", Descr: "<i4", Shape: []int{n}, Data: game_ids},
+		{Name: "move_number", Descr: "<i4", Shape: []int{n}, Data: move_numbers},
+	}, map[string][]byte{"metadata.json": metadata_json})
+	if err == nil {
+		err = file.Chmod(0o644)
+	}
+	if close_err := file.Close(); err == nil {
+		err = close_err
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return err
+	}
+
+	writer.Written = append(writer.Written, path)
+	writer.NextShard++
+	writer.Buffer = writer.Buffer[:0]
+	return nil
+}
@@ -0,0 +1,270 @@
+package sgf
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+)
+
+type Property struct {
+	Name   string
+	Values []string
+}
+
+type Node []Property
+
+func (node Node) Get(name string) (string, bool) {
+	for _, property := range node {
+		if property.Name == name && len(property.Values) > 0 {
+			return property.Values[0], true
+		}
+	}
+	return "", false
+}
+
+// Reads the nodes of the main line: the first variation is followed at each branching
+type parser struct {
+	input    string
+	position int
+}
+
+func (p *parser) SkipSpaces() {
+	for p.position < len(p.input) && strings.ContainsRune(" \t\r\n", rune(p.input[p.position])) {
+		p.position++
+	}
+}
+
+func (p *parser) Errorf(format string, args ...any) error {
+	return fmt.Errorf("sgf: offset %d: %s", p.position, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) Value() (string, error) {
+	// Value between brackets, with \ escaping the next character
+	p.position++ // [
+	var builder strings.Builder
+	for p.position < len(p.input) {
+		var c byte = p.input[p.position]
+		switch c {
+		case '\\':
+			p.position++
+			if p.position < len(p.input) && p.input[p.position] != '\n' { // An escaped newline is removed
+				builder.WriteByte(p.input[p.position])
+			}
+		case ']':
+			p.position++
+			return builder.String(), nil
+		default:
+			builder.WriteByte(c)
+		}
+		p.position++
+	}
+	return "", p.Errorf("unterminated value")
+}
+
+func (p *parser) Node() (Node, error) {
+	p.position++ // ;
+	var node Node
+	for {
+		p.SkipSpaces()
+		var start int = p.position
+		for p.position < len(p.input) && p.input[p.position] >= 'A' && p.input[p.position] <= 'Z' {
+			p.position++
+		}
+		if start == p.position {
+			return node, nil
+		}
+		var property Property = Property{Name: p.input[start:p.position]}
+		for p.SkipSpaces(); p.position < len(p.input) && p.input[p.position] == '['; p.SkipSpaces() {
+			value, err := p.Value()
+			if err != nil {
+				return nil, err
+			}
+			property.Values = append(property.Values, value)
+		}
+		if len(property.Values) == 0 {
+			return nil, p.Errorf("property %s has no value", property.Name)
+		}
+		node = append(node, property)
+	}
+}
+
+func (p *parser) Skip() error {
+	// Skips a variation that is not on the main line, its opening parenthesis already read
+	for depth := 1; depth > 0; {
+		if p.position >= len(p.input) {
+			return p.Errorf("unterminated variation")
+		}
+		switch p.input[p.position] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '[':
+			if _, err := p.Value(); err != nil {
+				return err
+			}
+			continue
+		}
+		p.position++
+	}
+	return nil
+}
+
+func (p *parser) MainLine() ([]Node, error) {
+	// Sequence of nodes of a game tree, its opening parenthesis already read
+	var nodes []Node
+	var followed bool = false
+	for {
+		p.SkipSpaces()
+		if p.position >= len(p.input) {
+			return nil, p.Errorf("unterminated game tree")
+		}
+		switch p.input[p.position] {
+		case ';':
+			if followed {
+				return nil, p.Errorf("node after a variation")
+			}
+			node, err := p.Node()
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		case '(':
+			p.position++
+			if followed {
+				if err := p.Skip(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			variation, err := p.MainLine()
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, variation...)
+			followed = true
+		case ')':
+			p.position++
+			return nodes, nil
+		default:
+			return nil, p.Errorf("unexpected %q", p.input[p.position])
+		}
+	}
+}
+
+func ParsePoint(value string, size int) (environment.Action, error) {
+	// "" and "tt" (on boards up to 19x19) are passes
+	if value == "" || (value == "tt" && size <= 19) {
+		return environment.Pass{}, nil
+	}
+	if len(value) != 2 {
+		return nil, fmt.Errorf("sgf: invalid point %q", value)
+	}
+	var j, i int = int(value[0] - 'a'), int(value[1] - 'a')
+	if i < 0 || i >= size || j < 0 || j >= size {
+		return nil, fmt.Errorf("sgf: point %q outside of the %dx%d board", value, size, size)
+	}
+	return environment.PutStone{I: i, J: j}, nil
+}
+
+func Parse(input string) (*Record, error) {
+	var p *parser = &parser{input: input}
+	p.SkipSpaces()
+	if p.position >= len(input) || input[p.position] != '(' {
+		return nil, p.Errorf("expected a game tree")
+	}
+	p.position++
+	nodes, err := p.MainLine()
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("sgf: empty game tree")
+	}
+
+	var root Node = nodes[0]
+	if game, ok := root.Get("GM"); ok && game != "1" {
+		return nil, fmt.Errorf("sgf: GM[%s] is not a game of go", game)
+	}
+	var size int = 19
+	if value, ok := root.Get("SZ"); ok {
+		// Rectangular boards ("9:13") are not supported
+		if size, err = strconv.Atoi(value); err != nil || size < 2 || size > 25 {
+			return nil, fmt.Errorf("sgf: unsupported board size SZ[%s]", value)
+		}
+	}
+	var komi float64 = 0
+	if value, ok := root.Get("KM"); ok {
+		if komi, err = strconv.ParseFloat(strings.TrimSpace(value), 64); err != nil {
+			return nil, fmt.Errorf("sgf: invalid komi KM[%s]", value)
+		}
+	}
+	var rules environment.Ruleset = environment.AreaScoring
+	if value, ok := root.Get("RU"); ok && strings.HasPrefix(strings.ToLower(value), "japanese") {
+		rules = environment.TerritoryScoring
+	}
+	var record *Record = NewRecord(size, komi, rules)
+	record.Result, _ = root.Get("RE")
+	record.PlayerBlack, _ = root.Get("PB")
+	record.PlayerWhite, _ = root.Get("PW")
+
+	var has_setup bool = false
+	for _, node := range nodes {
+		for _, property := range node {
+			switch property.Name {
+			case "AB", "AW", "AE":
+				if len(record.Moves) > 0 {
+					return nil, fmt.Errorf("sgf: setup property %s after the first move is not supported", property.Name)
+				}
+				var stone environment.Stone = map[string]environment.Stone{"AB": environment.Black, "AW": environment.White, "AE": environment.Empty}[property.Name]
+				for _, value := range property.Values {
+					// Compressed rectangles ("aa:cc") are not supported
+					action, err := ParsePoint(value, size)
+					if err != nil {
+						return nil, err
+					}
+					if point, ok := action.(environment.PutStone); ok {
+						record.Setup[point.I][point.J] = stone
+						has_setup = true
+					}
+				}
+			case "B", "W":
+				var player environment.Stone = environment.Black
+				if property.Name == "W" {
+					player = environment.White
+				}
+				action, err := ParsePoint(property.Values[0], size)
+				if err != nil {
+					return nil, err
+				}
+				record.Moves = append(record.Moves, Move{Player: player, Action: action})
+			}
+		}
+	}
+
+	// Handicap games start with White, unless PL says otherwise
+	record.FirstPlayer = environment.Black
+	if len(record.Moves) > 0 {
+		record.FirstPlayer = record.Moves[0].Player
+	} else if has_setup {
+		record.FirstPlayer = environment.White
+	}
+	if value, ok := root.Get("PL"); ok {
+		record.FirstPlayer = environment.Black
+		if strings.ToUpper(value) == "W" {
+			record.FirstPlayer = environment.White
+		}
+	}
+	return record, nil
+}
+
+func Load(path string) (*Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(data))
+}
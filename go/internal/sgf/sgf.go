@@ -0,0 +1,176 @@
+package sgf
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+)
+
+// Smart Game Format (https://www.red-bean.com/sgf/), only the main line of the game tree is kept
+
+type Move struct {
+	Player environment.Stone
+	Action environment.Action // PutStone or Pass
+}
+
+type Record struct {
+	Size        int
+	Komi        float64
+	Rules       environment.Ruleset
+	Setup       [][]environment.Stone // Stones on the board before the first move (handicap)
+	FirstPlayer environment.Stone
+	Moves       []Move
+	Result      string // As in the RE property, for example "B+R" or "W+2.5"
+	PlayerBlack string
+	PlayerWhite string
+}
+
+// Constructor
+func NewRecord(size int, komi float64, rules environment.Ruleset) *Record {
+	var setup [][]environment.Stone = make([][]environment.Stone, size)
+	for i := range setup {
+		setup[i] = make([]environment.Stone, size)
+	}
+	return &Record{
+		Size:        size,
+		Komi:        komi,
+		Rules:       rules,
+		Setup:       setup,
+		FirstPlayer: environment.Black,
+	}
+}
+
+func NewRecordFromGame(initial, final *environment.Game) *Record {
+	// Record of the moves played from the initial position to the final one
+	var record *Record = NewRecord(initial.Board.Height, final.Komi, final.Rules)
+	for i := range initial.Board.Matrix {
+		copy(record.Setup[i], initial.Board.Matrix[i])
+	}
+	record.FirstPlayer = initial.Board.CurrentPlayer
+	var player environment.Stone = initial.Board.CurrentPlayer
+	for _, action := range final.MoveHistory[len(initial.MoveHistory):] {
+		if _, is_resign := action.(environment.Resign); !is_resign {
+			record.Moves = append(record.Moves, Move{Player: player, Action: action})
+		}
+		player = player.Opponent()
+	}
+	if final.IsTerminal() {
+		record.Result = Result(final)
+	}
+	return record
+}
+
+// Methods
+func Result(game *environment.Game) string {
+	// "B+R", "W+2.5", or "0" for a draw as in the SGF specification
+	var winner string = "B"
+	switch game.GetWinner() {
+	case environment.White:
+		winner = "W"
+	case environment.Empty:
+		return "0"
+	}
+	if game.Board.Resigned != environment.Empty {
+		return winner + "+R"
+	}
+	var score environment.Score = game.ComputeScore()
+	return winner + "+" + strconv.FormatFloat(max(score.Black-score.White, score.White-score.Black), 'f', -1, 64)
+}
+
+func (record *Record) InitialGame() *environment.Game {
+	var game *environment.Game = environment.NewGameFromPosition(record.Setup, record.FirstPlayer, record.Komi)
+	game.Rules = record.Rules
+	return game
+}
+
+func (record *Record) Positions() ([]*environment.Game, error) {
+	// Position before the first move, then after each move. A move out of turn is preceded by a pass.
+	// The moves are checked, an illegal one would corrupt the groups of the board.
+	var game *environment.Game = record.InitialGame()
+	var positions []*environment.Game = []*environment.Game{game.DeepCopy()}
+	for move_idx, move := range record.Moves {
+		if move.Player != game.Board.CurrentPlayer && !game.IsTerminal() {
+			game.PlayAction(environment.Pass{})
+		}
+		if game.IsTerminal() {
+			return nil, fmt.Errorf("sgf: move %d is played after the end of the game", move_idx+1)
+		}
+		if stone, ok := move.Action.(environment.PutStone); ok {
+			if stone.I < 0 || stone.I >= game.Board.Height || stone.J < 0 || stone.J >= game.Board.Width {
+				return nil, fmt.Errorf("sgf: move %d is outside of the board", move_idx+1)
+			}
+			if !game.IsLegalAction(stone.I, stone.J) {
+				return nil, fmt.Errorf("sgf: move %d at %s is illegal", move_idx+1, environment.GtpVertex(stone, game.Board.Height))
+			}
+		}
+		game.PlayAction(move.Action)
+		positions = append(positions, game.DeepCopy())
+	}
+	return positions, nil
+}
+
+func Point(action environment.Action) string {
+	// Columns then rows, both from the top left corner, an empty point is a pass
+	if stone, ok := action.(environment.PutStone); ok {
+		return string(rune('a'+stone.J)) + string(rune('a'+stone.I))
+	}
+	return ""
+}
+
+func Escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `]`, `\]`).Replace(value)
+}
+
+func (record *Record) Encode() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "(;FF[4]GM[1]CA[UTF-8]SZ[%d]KM[%s]RU[%s]", record.Size, strconv.FormatFloat(record.Komi, 'f', -1, 64), record.Rules)
+	if record.PlayerBlack != "" {
+		fmt.Fprintf(&builder, "PB[%s]", Escape(record.PlayerBlack))
+	}
+	if record.PlayerWhite != "" {
+		fmt.Fprintf(&builder, "PW[%s]", Escape(record.PlayerWhite))
+	}
+	if record.Result != "" {
+		fmt.Fprintf(&builder, "RE[%s]", Escape(record.Result))
+	}
+	for _, color := range []environment.Stone{environment.Black, environment.White} {
+		var points []string
+		for i, row := range record.Setup {
+			for j, stone := range row {
+				if stone == color {
+					points = append(points, Point(environment.PutStone{I: i, J: j}))
+				}
+			}
+		}
+		if len(points) == 0 {
+			continue
+		}
+		var property string = "AB"
+		if color == environment.White {
+			property = "AW"
+		}
+		fmt.Fprintf(&builder, "%s[%s]", property, strings.Join(points, "]["))
+	}
+	if record.FirstPlayer == environment.White {
+		builder.WriteString("PL[W]")
+	}
+	for move_idx, move := range record.Moves {
+		if move_idx%10 == 0 {
+			builder.WriteString("\n")
+		}
+		var color string = "B"
+		if move.Player == environment.White {
+			color = "W"
+		}
+		fmt.Fprintf(&builder, ";%s[%s]", color, Point(move.Action))
+	}
+	builder.WriteString(")\n")
+	return builder.String()
+}
+
+func (record *Record) Save(path string) error {
+	return os.WriteFile(path, []byte(record.Encode()), 0644)
+}
@@ -0,0 +1,55 @@
+package sgf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPositionsRejectIllegalMoves(t *testing.T) {
+	for _, test := range []struct {
+		input string
+		err   string // Empty for a legal game
+	}{
+		{"(;SZ[5];B[cc];W[cd];B[dc])", ""},
+		{"(;SZ[5];B[cc];W[cc])", "move 2 at C3 is illegal"},
+		{"(;SZ[5];B[ba];W[dd];B[ab];W[aa])", "move 4 at A5 is illegal"}, // Suicide
+		{"(;SZ[5];B[];W[];B[cc])", "move 3 is played after the end of the game"},
+	} {
+		record, err := Parse(test.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		positions, err := record.Positions()
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s: %v", test.input, err)
+		case test.err == "" && len(positions) != len(record.Moves)+1:
+			t.Errorf("%s: %d positions for %d moves", test.input, len(positions), len(record.Moves))
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%s: got error %v, want %q", test.input, err, test.err)
+		}
+	}
+}
+
+func TestResult(t *testing.T) {
+	for _, test := range []struct {
+		input  string
+		result string
+	}{
+		{"(;SZ[5]KM[0];B[];W[])", "0"}, // Draw
+		{"(;SZ[5]KM[0.5];B[];W[])", "W+0.5"},
+		{"(;SZ[5]KM[-3];B[];W[])", "B+3"},
+	} {
+		record, err := Parse(test.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		positions, err := record.Positions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := Result(positions[len(positions)-1]); result != test.result {
+			t.Errorf("%s: got result %q, want %q", test.input, result, test.result)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package trainer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
+)
+
+// GameUploader streams the self-play games to the trainer as soon as they are played, over a single SubmitGames
+// stream that is opened again when it breaks. Games sent on a stream that breaks afterwards may be lost.
+type GameUploader struct {
+	Client     remote_trainer.TrainerClient
+	Records    chan *remote_trainer.GameRecord
+	Done       chan bool
+	MaxBackoff time.Duration
+	Mutex      sync.Mutex
+	Response   *remote_trainer.SubmitGamesResponse // Totals of the last closed stream
+	Err        error
+}
+
+// Constructor
+func NewGameUploader(client remote_trainer.TrainerClient, buffer int) *GameUploader {
+	var uploader *GameUploader = &GameUploader{
+		Client:     client,
+		Records:    make(chan *remote_trainer.GameRecord, buffer),
+		Done:       make(chan bool),
+		MaxBackoff: 30 * time.Second,
+	}
+	go uploader.Run()
+	return uploader
+}
+
+// Methods
+func (uploader *GameUploader) Submit(record *remote_trainer.GameRecord) {
+	// Blocks when the buffer is full, so that the workers wait for a trainer that is down instead of piling up games
+	uploader.Records <- record
+}
+
+func (uploader *GameUploader) OpenStream() remote_trainer.Trainer_SubmitGamesClient {
+	var backoff time.Duration = 100 * time.Millisecond
+	for {
+		stream, err := uploader.Client.SubmitGames(context.Background())
+		if err == nil {
+			return stream
+		}
+		println("Error opening the game stream:", err.Error())
+		time.Sleep(backoff)
+		backoff = min(2*backoff, uploader.MaxBackoff)
+	}
+}
+
+func (uploader *GameUploader) Run() {
+	defer close(uploader.Done)
+	var stream remote_trainer.Trainer_SubmitGamesClient = nil
+	for record := range uploader.Records {
+		for {
+			if stream == nil {
+				stream = uploader.OpenStream()
+			}
+			err := stream.Send(record)
+			if err == nil {
+				break
+			}
+			// The actual error is returned by CloseAndRecv, send the game again on a new stream
+			_, err = stream.CloseAndRecv()
+			if err != nil {
+				println("Game stream broken:", err.Error())
+			}
+			stream = nil
+		}
+	}
+	if stream == nil {
+		return
+	}
+	response, err := stream.CloseAndRecv()
+	uploader.Mutex.Lock()
+	defer uploader.Mutex.Unlock()
+	uploader.Response, uploader.Err = response, err
+}
+
+func (uploader *GameUploader) Close() (*remote_trainer.SubmitGamesResponse, error) {
+	// Sends the games left in the buffer and closes the stream
+	close(uploader.Records)
+	<-uploader.Done
+	uploader.Mutex.Lock()
+	defer uploader.Mutex.Unlock()
+	if uploader.Response == nil && uploader.Err == nil {
+		return &remote_trainer.SubmitGamesResponse{}, nil // No game was submitted
+	}
+	return uploader.Response, uploader.Err
+}
+
+// ModelWatcher polls the trainer for new model versions. The workers read Latest between games and switch
+// to the new version without restarting.
+type ModelWatcher struct {
+	Client   remote_trainer.TrainerClient
+	Interval time.Duration
+	ModelDir string // Where the weights sent by the trainer are saved, empty to ignore them
+	Mutex    sync.Mutex
+	Current  *remote_trainer.ModelInfo
+	OnUpdate func(model *remote_trainer.ModelInfo) // Called after each new version, nil to only poll
+	Done     chan bool
+}
+
+// Constructor
+func NewModelWatcher(client remote_trainer.TrainerClient, interval time.Duration, model_dir string) *ModelWatcher {
+	return &ModelWatcher{
+		Client:   client,
+		Interval: interval,
+		ModelDir: model_dir,
+		Current:  &remote_trainer.ModelInfo{},
+		Done:     make(chan bool),
+	}
+}
+
+// Methods
+func (watcher *ModelWatcher) Latest() *remote_trainer.ModelInfo {
+	watcher.Mutex.Lock()
+	defer watcher.Mutex.Unlock()
+	return watcher.Current
+}
+
+func (watcher *ModelWatcher) Poll() (bool, error) {
+	// Returns true if a new version was found
+	var current_version string = watcher.Latest().Version
+	model, err := watcher.Client.GetLatestModel(context.Background(), &remote_trainer.GetLatestModelRequest{CurrentVersion: current_version})
+	if err != nil {
+		return false, err
+	}
+	if model.Version == "" || model.Version == current_version {
+		return false, nil
+	}
+	if len(model.Weights) > 0 && watcher.ModelDir != "" {
+		var path string = filepath.Join(watcher.ModelDir, model.Version+".bin")
+		if err := os.MkdirAll(watcher.ModelDir, 0o755); err != nil {
+			return false, err
+		}
+		if err := os.WriteFile(path, model.Weights, 0o644); err != nil {
+			return false, err
+		}
+		model = &remote_trainer.ModelInfo{Version: model.Version, Path: path} // The weights are not kept in memory
+	}
+
+	watcher.Mutex.Lock()
+	watcher.Current = model
+	watcher.Mutex.Unlock()
+	if watcher.OnUpdate != nil {
+		watcher.OnUpdate(model)
+	}
+	return true, nil
+}
+
+func (watcher *ModelWatcher) Start() {
+	go func() {
+		var ticker *time.Ticker = time.NewTicker(watcher.Interval)
+		defer ticker.Stop()
+		for {
+			if _, err := watcher.Poll(); err != nil {
+				println("Error getting the latest model:", err.Error())
+			}
+			select {
+			case <-watcher.Done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (watcher *ModelWatcher) Stop() {
+	close(watcher.Done)
+}
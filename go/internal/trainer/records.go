@@ -0,0 +1,45 @@
+package trainer
+
+import (
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/features"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/selfplay"
+)
+
+func NewGameRecord(worker_id string, model_version string, game *environment.Game, examples []selfplay.Example) *remote_trainer.GameRecord {
+	var record *remote_trainer.GameRecord = &remote_trainer.GameRecord{
+		WorkerId:     worker_id,
+		ModelVersion: model_version,
+		Height:       int32(game.Board.Height),
+		Width:        int32(game.Board.Width),
+		Komi:         float32(game.Komi),
+		NumPlanes:    features.NumPlanes,
+		BlackResult:  float32(game.GameResult(environment.Black)),
+		Positions:    make([]*remote_trainer.TrainingPosition, len(examples)),
+	}
+	for example_idx, example := range examples {
+		record.Positions[example_idx] = &remote_trainer.TrainingPosition{
+			Planes:     example.Features,
+			Policy:     example.Policy,
+			Value:      example.Value,
+			MoveNumber: int32(example.MoveNumber),
+		}
+	}
+	return record
+}
+
+func RecordExamples(record *remote_trainer.GameRecord, game_id int) []selfplay.Example {
+	// Inverse of NewGameRecord, to write the received games as training shards
+	var examples []selfplay.Example = make([]selfplay.Example, len(record.Positions))
+	for position_idx, position := range record.Positions {
+		examples[position_idx] = selfplay.Example{
+			Features:   position.Planes,
+			Policy:     position.Policy,
+			Value:      position.Value,
+			GameId:     game_id,
+			MoveNumber: int(position.MoveNumber),
+		}
+	}
+	return examples
+}
@@ -0,0 +1,141 @@
+package trainer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/selfplay"
+)
+
+// StubTrainer is an in-memory trainer for local runs and tests: it counts the games it receives, optionally
+// writes them as training shards, serves the models published with PublishModel and promotes the candidates
+// that win at least PromotionWinRate of their match.
+type StubTrainer struct {
+	remote_trainer.UnimplementedTrainerServer
+	Mutex            sync.Mutex
+	Models           map[string]*remote_trainer.ModelInfo
+	LatestVersion    string
+	PromotionWinRate float64
+	Games            int64
+	Positions        int64
+	Matches          []*remote_trainer.MatchResult
+	Writers          map[string]*selfplay.ShardWriter // One writer per board size and komi, nil map to keep nothing
+	OutDir           string
+	ShardSize        int
+}
+
+// Constructor
+func NewStubTrainer(out_dir string, shard_size int) *StubTrainer {
+	var stub *StubTrainer = &StubTrainer{
+		Models:           make(map[string]*remote_trainer.ModelInfo),
+		PromotionWinRate: 0.55,
+		OutDir:           out_dir,
+		ShardSize:        shard_size,
+	}
+	if out_dir != "" {
+		stub.Writers = make(map[string]*selfplay.ShardWriter)
+	}
+	return stub
+}
+
+// Methods
+func (stub *StubTrainer) PublishModel(model *remote_trainer.ModelInfo, make_latest bool) {
+	stub.Mutex.Lock()
+	defer stub.Mutex.Unlock()
+	stub.Models[model.Version] = model
+	if make_latest {
+		stub.LatestVersion = model.Version
+	}
+}
+
+func (stub *StubTrainer) Store(record *remote_trainer.GameRecord) error {
+	// Called with the mutex held
+	if stub.Writers == nil {
+		return nil
+	}
+	var key string = fmt.Sprintf("%dx%d-komi%g", record.Height, record.Width, record.Komi)
+	writer, ok := stub.Writers[key]
+	if !ok {
+		var err error
+		writer, err = selfplay.NewShardWriter(stub.OutDir, key, stub.ShardSize, record.ModelVersion, float64(record.Komi), int(record.Height), int(record.Width))
+		if err != nil {
+			return err
+		}
+		stub.Writers[key] = writer
+	}
+	// A shard only holds the games of one model, the positions of the previous one are written first
+	if err := writer.SetModelVersion(record.ModelVersion); err != nil {
+		return err
+	}
+	return writer.Add(RecordExamples(record, int(stub.Games)))
+}
+
+func (stub *StubTrainer) SubmitGames(stream remote_trainer.Trainer_SubmitGamesServer) error {
+	var response *remote_trainer.SubmitGamesResponse = &remote_trainer.SubmitGamesResponse{}
+	for {
+		record, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(response)
+		}
+		if err != nil {
+			return err
+		}
+		stub.Mutex.Lock()
+		err = stub.Store(record)
+		stub.Games++
+		stub.Positions += int64(len(record.Positions))
+		stub.Mutex.Unlock()
+		if err != nil {
+			return err
+		}
+		response.GamesReceived++
+		response.PositionsReceived += int64(len(record.Positions))
+	}
+}
+
+func (stub *StubTrainer) GetLatestModel(ctx context.Context, request *remote_trainer.GetLatestModelRequest) (*remote_trainer.ModelInfo, error) {
+	stub.Mutex.Lock()
+	defer stub.Mutex.Unlock()
+	model, ok := stub.Models[stub.LatestVersion]
+	if !ok {
+		return &remote_trainer.ModelInfo{}, nil // Nothing published yet
+	}
+	if request.CurrentVersion == model.Version {
+		return &remote_trainer.ModelInfo{Version: model.Version, Path: model.Path}, nil
+	}
+	return model, nil
+}
+
+func (stub *StubTrainer) ReportMatchResult(ctx context.Context, result *remote_trainer.MatchResult) (*remote_trainer.ReportMatchResultResponse, error) {
+	stub.Mutex.Lock()
+	defer stub.Mutex.Unlock()
+	stub.Matches = append(stub.Matches, result)
+
+	// Draws count as half a win
+	var games int32 = result.Wins + result.Losses + result.Draws
+	if games == 0 {
+		return &remote_trainer.ReportMatchResultResponse{Promoted: false}, nil
+	}
+	var win_rate float64 = (float64(result.Wins) + 0.5*float64(result.Draws)) / float64(games)
+	_, is_known := stub.Models[result.CandidateVersion]
+	var promoted bool = is_known && win_rate >= stub.PromotionWinRate
+	if promoted {
+		stub.LatestVersion = result.CandidateVersion
+	}
+	return &remote_trainer.ReportMatchResultResponse{Promoted: promoted}, nil
+}
+
+func (stub *StubTrainer) Close() error {
+	// Writes the positions left in the shard buffers
+	stub.Mutex.Lock()
+	defer stub.Mutex.Unlock()
+	for _, writer := range stub.Writers {
+		if err := writer.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
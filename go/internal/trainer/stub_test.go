@@ -0,0 +1,90 @@
+package trainer
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/features"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/selfplay"
+)
+
+func NewTestRecord(model_version string, positions int) *remote_trainer.GameRecord {
+	// Game on an empty 5x5 board, only the shapes of the positions matter
+	var record *remote_trainer.GameRecord = &remote_trainer.GameRecord{
+		ModelVersion: model_version,
+		Height:       5,
+		Width:        5,
+		Komi:         6.5,
+		NumPlanes:    features.NumPlanes,
+	}
+	for move := 0; move < positions; move++ {
+		record.Positions = append(record.Positions, &remote_trainer.TrainingPosition{
+			Planes:     make([]float32, features.NumPlanes*25),
+			Policy:     make([]float32, features.PolicySize(5, 5)),
+			MoveNumber: int32(move),
+		})
+	}
+	return record
+}
+
+func ReadShardMetadata(t *testing.T, path string) selfplay.ShardMetadata {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	file, err := reader.Open("metadata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var metadata selfplay.ShardMetadata
+	if err := json.Unmarshal(content, &metadata); err != nil {
+		t.Fatal(err)
+	}
+	return metadata
+}
+
+func TestStoreSplitsShardsByModelVersion(t *testing.T) {
+	var stub *StubTrainer = NewStubTrainer(t.TempDir(), 100)
+	for _, record := range []*remote_trainer.GameRecord{
+		NewTestRecord("model-1", 3),
+		NewTestRecord("model-1", 2),
+		NewTestRecord("model-2", 4),
+	} {
+		if err := stub.Store(record); err != nil {
+			t.Fatal(err)
+		}
+		stub.Games++
+	}
+	if err := stub.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var writer *selfplay.ShardWriter = stub.Writers["5x5-komi6.5"]
+	if writer == nil {
+		t.Fatal("no shard writer for the 5x5 games")
+	}
+	if len(writer.Written) != 2 {
+		t.Fatalf("expected 2 shards, got %v", writer.Written)
+	}
+	var expected []selfplay.ShardMetadata = []selfplay.ShardMetadata{
+		{ModelVersion: "model-1", Positions: 5, Games: 2},
+		{ModelVersion: "model-2", Positions: 4, Games: 1},
+	}
+	for shard_idx, path := range writer.Written {
+		var metadata selfplay.ShardMetadata = ReadShardMetadata(t, path)
+		if metadata.ModelVersion != expected[shard_idx].ModelVersion || metadata.Positions != expected[shard_idx].Positions || metadata.Games != expected[shard_idx].Games {
+			t.Errorf("shard %d: got version %q with %d positions of %d games, want %q with %d of %d", shard_idx,
+				metadata.ModelVersion, metadata.Positions, metadata.Games,
+				expected[shard_idx].ModelVersion, expected[shard_idx].Positions, expected[shard_idx].Games)
+		}
+	}
+}
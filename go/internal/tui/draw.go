@@ -0,0 +1,197 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/render"
+)
+
+// Colors of the 256 color palette
+const (
+	BoardColor     int = 179
+	GridColor      int = 94
+	BlackColor     int = 16
+	WhiteColor     int = 231
+	MarkerColor    int = 160
+	CandidateColor int = 21
+	DimColor       int = 245
+)
+
+const LabelledCandidates int = 5 // Candidates numbered on the board
+const PanelPVLength int = 6
+
+// Characters of the board, each point takes two columns
+const (
+	StoneChar     string = "●"
+	LastStoneChar string = "◉"
+	EmptyChar     string = "·"
+	StarChar      string = "+"
+	KoChar        string = "□"
+)
+
+func (session *Session) LiveSearch(game *environment.Game) (*agents.SearchInfo, bool) {
+	// Last search info, and whether it is about the current position
+	var info *agents.SearchInfo = session.Search.Get()
+	if info == nil {
+		return nil, false
+	}
+	return info, info.MoveNumber == len(game.MoveHistory) && info.Player == game.Board.CurrentPlayer && info.BoardHeight == game.Board.Height
+}
+
+func (session *Session) Frame() []string {
+	// Board on the left, panel on its right, help at the bottom
+	var game *environment.Game = session.Game.Get()
+	var board_lines []string = session.BoardLines(game)
+	var panel_lines []string = session.PanelLines(game)
+	var board_width int = 3 + 2*game.Board.Width + 3
+	var lines []string
+	for line_idx := 0; line_idx < max(len(board_lines), len(panel_lines)); line_idx++ {
+		var line string = strings.Repeat(" ", board_width)
+		if line_idx < len(board_lines) {
+			line = board_lines[line_idx]
+		}
+		if line_idx < len(panel_lines) {
+			line += "   " + panel_lines[line_idx]
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "")
+	if session.Message != "" {
+		lines = append(lines, Foreground(MarkerColor)+session.Message)
+	} else {
+		lines = append(lines, "")
+	}
+	lines = append(lines, Foreground(DimColor)+"arrows/hjkl move, enter/space play, p pass, r resign, s pause, q quit")
+	return lines
+}
+
+func (session *Session) BoardLines(game *environment.Game) []string {
+	var board *environment.Board = game.Board
+	// Only the labels and the star points, the terminal has no pixels
+	var geometry render.Geometry = render.NewGeometry(0, 0, 0, board.Height, board.Width, 0, 0)
+
+	// Markers
+	var stars map[environment.Position]bool = map[environment.Position]bool{}
+	for _, pos := range geometry.StarPoints() {
+		stars[pos] = true
+	}
+	var ko_points map[environment.Position]bool = map[environment.Position]bool{}
+	if !game.IsTerminal() {
+		for _, pos := range game.KoPoints() {
+			ko_points[pos] = true
+		}
+	}
+	var candidates map[environment.Position]int = map[environment.Position]int{}
+	if info, live := session.LiveSearch(game); live {
+		for candidate_idx, candidate := range info.Candidates[:min(LabelledCandidates, len(info.Candidates))] {
+			if stone, ok := candidate.Action.(environment.PutStone); ok && candidate.Visits > 0 {
+				candidates[environment.NewPosition(stone.I, stone.J)] = candidate_idx + 1
+			}
+		}
+	}
+	last_stone, has_last_stone := render.LastStone(game, 0)
+	var show_cursor bool = session.CurrentHuman() != nil
+
+	var header string = "   "
+	for j := 0; j < board.Width; j++ {
+		header += geometry.ColumnLabel(j) + " "
+	}
+	header += "   " // As wide as the rows, for the panel
+	var lines []string = []string{header}
+	for i := 0; i < board.Height; i++ {
+		var line strings.Builder
+		fmt.Fprintf(&line, "%2s %s", geometry.RowLabel(i), Background(BoardColor))
+		for j := 0; j < board.Width; j++ {
+			var pos environment.Position = environment.NewPosition(i, j)
+			var cell string
+			switch stone := board.Matrix[i][j]; {
+			case stone != environment.Empty:
+				var char string = StoneChar
+				if has_last_stone && last_stone.I == i && last_stone.J == j {
+					char = LastStoneChar
+				}
+				var stone_color int = BlackColor
+				if stone == environment.White {
+					stone_color = WhiteColor
+				}
+				cell = Foreground(stone_color) + char
+			case ko_points[pos]:
+				cell = Foreground(MarkerColor) + KoChar
+			case candidates[pos] > 0:
+				cell = Bold + Foreground(CandidateColor) + fmt.Sprint(candidates[pos])
+			case stars[pos]:
+				cell = Foreground(GridColor) + StarChar
+			default:
+				cell = Foreground(GridColor) + EmptyChar
+			}
+			if show_cursor && session.Cursor == pos {
+				cell = Reverse + cell
+			}
+			line.WriteString(cell + Reset + Background(BoardColor))
+			if j < board.Width-1 {
+				line.WriteString(" ")
+			}
+		}
+		fmt.Fprintf(&line, " %s %-2s", Reset, geometry.RowLabel(i))
+		lines = append(lines, line.String())
+	}
+	return append(lines, header)
+}
+
+func (session *Session) PanelLines(game *environment.Game) []string {
+	var board *environment.Board = game.Board
+	var lines []string
+
+	// Players, the one to move first
+	for _, player := range []environment.Stone{environment.Black, environment.White} {
+		var marker string = "  "
+		if player == board.CurrentPlayer && !game.IsTerminal() {
+			marker = "▶ "
+		}
+		lines = append(lines, Bold+marker+session.PlayerName(player))
+	}
+	lines = append(lines, fmt.Sprintf("Move %d, komi %.1f, %s", len(game.MoveHistory)+1, game.Komi, game.Rules))
+	lines = append(lines, fmt.Sprintf("Captures: Black %d, White %d", board.Captures.Black, board.Captures.White))
+	lines = append(lines, "")
+
+	// State of the game
+	switch {
+	case game.IsTerminal():
+		lines = append(lines, Bold+Foreground(MarkerColor)+Result(game))
+	case session.IsPaused.Get():
+		lines = append(lines, Bold+"Paused (s: resume)")
+	case session.CurrentHuman() != nil:
+		lines = append(lines, Bold+"Your move as "+ColorName(board.CurrentPlayer))
+	default:
+		lines = append(lines, "Thinking...")
+	}
+	lines = append(lines, "")
+
+	// Search of the bots
+	info, live := session.LiveSearch(game)
+	if info == nil {
+		return lines
+	}
+	var title string = "Search"
+	if !live {
+		title = fmt.Sprintf("Search of move %d", info.MoveNumber+1)
+	}
+	lines = append(lines, fmt.Sprintf("%s: %d visits, %.0f sim/s, %s", title, info.RootVisits, info.SimulationsPerSecond, info.Elapsed.Round(100*time.Millisecond)))
+	lines = append(lines, fmt.Sprintf("%s winrate %.1f%%", ColorName(info.Player), 50*(info.RootValue+1)))
+	for candidate_idx, candidate := range info.Candidates[:min(LabelledCandidates, len(info.Candidates))] {
+		if candidate.Visits == 0 {
+			break
+		}
+		var label string = fmt.Sprintf("%d", candidate_idx+1)
+		if live {
+			label = Bold + Foreground(CandidateColor) + label + Reset
+		}
+		var pv []environment.Action = candidate.PV[:min(PanelPVLength, len(candidate.PV))]
+		lines = append(lines, fmt.Sprintf("%s %-4s %5.1f%% %7d  %s", label, environment.GtpVertex(candidate.Action, info.BoardHeight), 100*candidate.Winrate, candidate.Visits, Foreground(DimColor)+FormatPV(pv, info.BoardHeight)))
+	}
+	return lines
+}
@@ -0,0 +1,220 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/utils"
+)
+
+const SearchInfoInterval time.Duration = 250 * time.Millisecond
+const RefreshInterval time.Duration = 100 * time.Millisecond
+
+// Game played in the terminal, by humans at the keyboard or bots
+type Session struct {
+	Game        *utils.LockedPointer[environment.Game]
+	InitialGame *environment.Game
+	BlackAgent  agents.Agent
+	WhiteAgent  agents.Agent
+	BlackName   string
+	WhiteName   string
+	Search      *utils.LockedPointer[agents.SearchInfo] // Last snapshot of a bot's search
+	Cursor      environment.Position                    // Point selected with the arrows
+	Message     string                                  // Shown under the board until the next key
+	IsPaused    *utils.LockedBool
+	Closed      *utils.LockedBool
+	Redraw      chan bool // Signals a new position, buffered so that the players never wait for the screen
+}
+
+// Constructor
+func NewSession(game *environment.Game, black_agent, white_agent agents.Agent, black_name, white_name string) *Session {
+	var session *Session = &Session{
+		Game:        utils.NewLockedPointer(game),
+		InitialGame: game.DeepCopy(),
+		BlackAgent:  black_agent,
+		WhiteAgent:  white_agent,
+		BlackName:   black_name,
+		WhiteName:   white_name,
+		Search:      utils.NewLockedPointer[agents.SearchInfo](nil),
+		Cursor:      environment.NewPosition(game.Board.Height/2, game.Board.Width/2),
+		IsPaused:    utils.NewLockedBool(false),
+		Closed:      utils.NewLockedBool(false),
+		Redraw:      make(chan bool, 1),
+	}
+	for _, agent := range []agents.Agent{black_agent, white_agent} {
+		if mcts_agent, ok := agent.(*agents.MctsAgent); ok {
+			mcts_agent.InfoCandidates = 5
+			mcts_agent.SetInfoCallback(func(info agents.SearchInfo) {
+				session.Search.Set(&info)
+				session.RequestRedraw()
+			}, SearchInfoInterval)
+		}
+	}
+	return session
+}
+
+// Methods
+func (session *Session) RequestRedraw() {
+	select {
+	case session.Redraw <- true:
+	default:
+	}
+}
+
+func (session *Session) CurrentAgent() agents.Agent {
+	if session.Game.Get().Board.CurrentPlayer == environment.White {
+		return session.WhiteAgent
+	}
+	return session.BlackAgent
+}
+
+func (session *Session) CurrentHuman() *agents.HumanAgent {
+	// Human player whose move is awaited, nil if it is a bot's turn
+	if human, ok := session.CurrentAgent().(*agents.HumanAgent); ok && human.Waiting.Get() {
+		return human
+	}
+	return nil
+}
+
+func (session *Session) PlayGame() {
+	// Asks the players for their moves until the game is over, in the background
+	for !session.Closed.Get() && !session.Game.Get().IsTerminal() {
+		var agent agents.Agent = session.CurrentAgent()
+		var game *environment.Game = session.Game.Get().DeepCopy()
+		var action environment.Action = agent.SelectAction(game.DeepCopy())
+		for session.IsPaused.Get() && !session.Closed.Get() {
+			time.Sleep(RefreshInterval)
+		}
+		if session.Closed.Get() {
+			return
+		}
+		game.PlayAction(action)
+		session.Game.Set(game)
+		session.RequestRedraw()
+
+		// Keep searching while the other player thinks, unless it is the same agent
+		var waiting_agent agents.Agent = session.BlackAgent
+		if agent == session.BlackAgent {
+			waiting_agent = session.WhiteAgent
+		}
+		if ponderer, ok := agent.(agents.Ponderer); ok && agent != waiting_agent && !game.IsTerminal() {
+			ponderer.StartPondering(game)
+		}
+	}
+	session.StopPondering()
+}
+
+func (session *Session) StopPondering() {
+	for _, agent := range []agents.Agent{session.BlackAgent, session.WhiteAgent} {
+		if ponderer, ok := agent.(agents.Ponderer); ok {
+			ponderer.StopPondering()
+		}
+	}
+}
+
+func (session *Session) Close() {
+	// Stops the players, a search already running finishes in the background
+	session.Closed.Set(true)
+	session.StopPondering()
+	for _, agent := range []agents.Agent{session.BlackAgent, session.WhiteAgent} {
+		if human, ok := agent.(*agents.HumanAgent); ok {
+			human.Play(environment.Resign{}) // Unblocks PlayGame
+		}
+	}
+}
+
+func (session *Session) HandleKey(key Key) {
+	var game *environment.Game = session.Game.Get()
+	var board *environment.Board = game.Board
+	session.Message = ""
+	switch key {
+	case KeyUp:
+		session.Cursor.First = max(0, session.Cursor.First-1)
+	case KeyDown:
+		session.Cursor.First = min(board.Height-1, session.Cursor.First+1)
+	case KeyLeft:
+		session.Cursor.Second = max(0, session.Cursor.Second-1)
+	case KeyRight:
+		session.Cursor.Second = min(board.Width-1, session.Cursor.Second+1)
+	case KeyPause:
+		session.IsPaused.Set(!session.IsPaused.Get())
+	case KeyPlay, KeyPass, KeyResign:
+		var human *agents.HumanAgent = session.CurrentHuman()
+		if human == nil {
+			session.Message = "Wait for your turn"
+			return
+		}
+		switch key {
+		case KeyPass:
+			human.Play(environment.Pass{})
+		case KeyResign:
+			human.Play(environment.Resign{})
+		default:
+			if !game.IsLegalAction(session.Cursor.First, session.Cursor.Second) {
+				session.Message = fmt.Sprintf("%s is not a legal move", environment.GtpVertex(environment.PutStone{I: session.Cursor.First, J: session.Cursor.Second}, board.Height))
+				return
+			}
+			human.Play(environment.PutStone{I: session.Cursor.First, J: session.Cursor.Second})
+		}
+	}
+}
+
+func (session *Session) Run(terminal *Terminal) {
+	// Main loop: keys, new positions and search updates redraw the screen until q is pressed
+	var keys chan Key = make(chan Key, 16)
+	go terminal.ReadKeys(keys)
+	go session.PlayGame()
+	var ticker *time.Ticker = time.NewTicker(RefreshInterval)
+	defer ticker.Stop()
+	for {
+		terminal.DrawFrame(session.Frame())
+		select {
+		case key := <-keys:
+			if key == KeyQuit {
+				session.Close()
+				return
+			}
+			session.HandleKey(key)
+		case <-session.Redraw:
+		case <-ticker.C:
+			// Catches what nobody signals, such as a human player starting to wait for a move
+		}
+	}
+}
+
+func ColorName(player environment.Stone) string {
+	if player == environment.White {
+		return "White"
+	}
+	return "Black"
+}
+
+func (session *Session) PlayerName(player environment.Stone) string {
+	if player == environment.White {
+		return "White (" + session.WhiteName + ")"
+	}
+	return "Black (" + session.BlackName + ")"
+}
+
+func Result(game *environment.Game) string {
+	var winner string = ColorName(game.GetWinner())
+	if game.Board.Resigned != environment.Empty {
+		return winner + " wins by resignation"
+	}
+	var score environment.Score = game.ComputeScore()
+	if game.GetWinner() == environment.Empty {
+		return fmt.Sprintf("Draw, %.1f to %.1f", score.Black, score.White)
+	}
+	return fmt.Sprintf("%s wins, %.1f to %.1f", winner, score.Black, score.White)
+}
+
+func FormatPV(pv []environment.Action, height int) string {
+	var vertices []string
+	for _, action := range pv {
+		vertices = append(vertices, environment.GtpVertex(action, height))
+	}
+	return strings.Join(vertices, " ")
+}
@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+type Key int
+
+const (
+	KeyNone Key = iota
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyPlay // Enter or space
+	KeyPass
+	KeyResign
+	KeyPause
+	KeyQuit
+)
+
+// ANSI escape sequences
+const (
+	ClearScreen     string = "\x1b[2J"
+	CursorHome      string = "\x1b[H"
+	ClearLine       string = "\x1b[K"
+	ClearBelow      string = "\x1b[J"
+	HideCursor      string = "\x1b[?25l"
+	ShowCursor      string = "\x1b[?25h"
+	AlternateScreen string = "\x1b[?1049h"
+	MainScreen      string = "\x1b[?1049l"
+	Reset           string = "\x1b[0m"
+	Bold            string = "\x1b[1m"
+	Reverse         string = "\x1b[7m"
+)
+
+func Foreground(color int) string {
+	// One of the 256 colors of xterm
+	return "\x1b[38;5;" + strconv.Itoa(color) + "m"
+}
+
+func Background(color int) string {
+	return "\x1b[48;5;" + strconv.Itoa(color) + "m"
+}
+
+// Terminal in raw mode, drawn on the alternate screen so that the shell is left as it was
+type Terminal struct {
+	Input    *os.File
+	Output   *bufio.Writer
+	OldState *term.State
+}
+
+// Constructor
+func OpenTerminal() (*Terminal, error) {
+	var input *os.File = os.Stdin
+	if !term.IsTerminal(int(input.Fd())) {
+		return nil, errors.New("the terminal UI needs an interactive terminal, run it with ssh -t over SSH")
+	}
+	old_state, err := term.MakeRaw(int(input.Fd()))
+	if err != nil {
+		return nil, err
+	}
+	var terminal *Terminal = &Terminal{
+		Input:    input,
+		Output:   bufio.NewWriterSize(os.Stdout, 1<<16),
+		OldState: old_state,
+	}
+	terminal.Output.WriteString(AlternateScreen + HideCursor + ClearScreen)
+	terminal.Output.Flush()
+	return terminal, nil
+}
+
+// Methods
+func (terminal *Terminal) Close() {
+	terminal.Output.WriteString(Reset + ShowCursor + MainScreen)
+	terminal.Output.Flush()
+	term.Restore(int(terminal.Input.Fd()), terminal.OldState)
+}
+
+func (terminal *Terminal) DrawFrame(lines []string) {
+	// Redraws over the previous frame line by line, clearing the rest of each line, which does not flicker
+	terminal.Output.WriteString(CursorHome)
+	for _, line := range lines {
+		terminal.Output.WriteString(line + Reset + ClearLine + "\r\n")
+	}
+	terminal.Output.WriteString(ClearBelow)
+	terminal.Output.Flush()
+}
+
+func ParseKeys(input []byte) []Key {
+	// Arrows are sent as ESC [ A or ESC O A, the vi keys work too
+	var keys []Key
+	for i := 0; i < len(input); i++ {
+		if input[i] == 0x1b && i+2 < len(input) && (input[i+1] == '[' || input[i+1] == 'O') {
+			switch input[i+2] {
+			case 'A':
+				keys = append(keys, KeyUp)
+			case 'B':
+				keys = append(keys, KeyDown)
+			case 'C':
+				keys = append(keys, KeyRight)
+			case 'D':
+				keys = append(keys, KeyLeft)
+			}
+			i += 2
+			continue
+		}
+		switch strings.ToLower(string(input[i])) {
+		case "k":
+			keys = append(keys, KeyUp)
+		case "j":
+			keys = append(keys, KeyDown)
+		case "h":
+			keys = append(keys, KeyLeft)
+		case "l":
+			keys = append(keys, KeyRight)
+		case "\r", "\n", " ":
+			keys = append(keys, KeyPlay)
+		case "p":
+			keys = append(keys, KeyPass)
+		case "r":
+			keys = append(keys, KeyResign)
+		case "s":
+			keys = append(keys, KeyPause)
+		case "q", "\x03", "\x04": // Ctrl-C and Ctrl-D quit too, raw mode does not turn them into signals
+			keys = append(keys, KeyQuit)
+		}
+	}
+	return keys
+}
+
+func (terminal *Terminal) ReadKeys(keys chan Key) {
+	// Sends the keys pressed until the input is closed
+	var buffer []byte = make([]byte, 64)
+	for {
+		n, err := terminal.Input.Read(buffer)
+		for _, key := range ParseKeys(buffer[:n]) {
+			keys <- key
+		}
+		if err != nil {
+			keys <- KeyQuit
+			return
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/render"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const AnalysisInterval time.Duration = 100 * time.Millisecond
+
+// Candidates labelled with their winrate and visits, the others are only shaded
+const AnalysisLabelledCandidates int = 5
+
+func (app *App) WatchSearch(agent agents.Agent, board_size int) {
+	// Makes the agent publish snapshots of its root to the overlay and the graph while it searches
+	mcts_agent, ok := agent.(*agents.MctsAgent)
+	if !ok {
+		return
+	}
+	mcts_agent.InfoCandidates = board_size*board_size + 1 // Every point is shaded, not only the best ones
+	mcts_agent.SetInfoCallback(func(info agents.SearchInfo) {
+		app.Analysis.Set(&info)
+		app.Graph.AddSearch(info)
+	}, AnalysisInterval)
+}
+
+func (app *App) CurrentAnalysis() *agents.SearchInfo {
+	// Last snapshot of a search of the position shown, nil if there is none
+	var info *agents.SearchInfo = app.Analysis.Get()
+	var game *environment.Game = app.Game.Get()
+	if info == nil || len(info.Candidates) == 0 || info.MoveNumber != len(game.MoveHistory) || info.Player != game.Board.CurrentPlayer || info.BoardHeight != game.Board.Height {
+		return nil
+	}
+	return info
+}
+
+func FormatVisits(visits int) string {
+	switch {
+	case visits >= 10000:
+		return fmt.Sprintf("%dk", visits/1000)
+	case visits >= 1000:
+		return fmt.Sprintf("%.1fk", float64(visits)/1000)
+	default:
+		return fmt.Sprint(visits)
+	}
+}
+
+func (app *App) DrawAnalysis(ebiten_image *ebiten.Image) {
+	const antialias bool = true
+	if !app.ShowAnalysis.Get() {
+		return
+	}
+	var info *agents.SearchInfo = app.CurrentAnalysis()
+	if info == nil {
+		return
+	}
+	var game *environment.Game = app.Game.Get()
+	var radius float32 = app.CellSize() * app.UIMetadata.StoneRadiusScale
+	var best agents.CandidateInfo = info.Candidates[0]
+	var max_visits int = best.Visits
+
+	// Visit count shading, stronger on the points the search looks at the most
+	for _, candidate := range info.Candidates {
+		stone, ok := candidate.Action.(environment.PutStone)
+		if !ok || max_visits == 0 {
+			continue
+		}
+		var cx, cy float32 = app.UIMetadata.Margin.Left + app.CellSize()*float32(stone.J), app.UIMetadata.Margin.Top + app.CellSize()*float32(stone.I)
+		var fraction float32 = float32(candidate.Visits) / float32(max_visits)
+		vector.FillCircle(ebiten_image, cx, cy, radius, render.HeatColor(fraction), antialias)
+	}
+
+	// Principal variation of the best move as numbered ghost stones, the best move itself is labelled below
+	var player environment.Stone = info.Player
+	var drawn map[environment.PutStone]bool = make(map[environment.PutStone]bool)
+	for move_idx, action := range best.PV {
+		stone, ok := action.(environment.PutStone)
+		if ok && move_idx > 0 && !drawn[stone] && game.Board.Matrix[stone.I][stone.J] == environment.Empty {
+			drawn[stone] = true
+			var cx, cy float32 = app.UIMetadata.Margin.Left + app.CellSize()*float32(stone.J), app.UIMetadata.Margin.Top + app.CellSize()*float32(stone.I)
+			var ghost_color color.Color = color.NRGBA{0, 0, 0, 150}
+			var text_color color.Color = color.White
+			if player == environment.White {
+				ghost_color = color.NRGBA{255, 255, 255, 170}
+				text_color = color.Black
+			}
+			vector.FillCircle(ebiten_image, cx, cy, radius, ghost_color, antialias)
+			app.UIMetadata.DrawColoredText(ebiten_image, fmt.Sprint(move_idx+1), float64(cx), float64(cy), text.AlignCenter, text_color)
+		}
+		player = player.Opponent()
+	}
+
+	// Winrate and visits of the top candidates, for the player to move
+	for candidate_idx, candidate := range info.Candidates[:min(AnalysisLabelledCandidates, len(info.Candidates))] {
+		stone, ok := candidate.Action.(environment.PutStone)
+		if !ok || drawn[stone] {
+			continue
+		}
+		var cx, cy float32 = app.UIMetadata.Margin.Left + app.CellSize()*float32(stone.J), app.UIMetadata.Margin.Top + app.CellSize()*float32(stone.I)
+		if candidate_idx == 0 {
+			vector.StrokeCircle(ebiten_image, cx, cy, radius, app.UIMetadata.Px(2), color.RGBA{80, 220, 255, 255}, antialias)
+		}
+		var line_height float64 = float64(app.UIMetadata.Px(6))
+		app.UIMetadata.DrawText(ebiten_image, fmt.Sprintf("%.0f%%", 100*candidate.Winrate), float64(cx), float64(cy)-line_height, text.AlignCenter)
+		app.UIMetadata.DrawText(ebiten_image, FormatVisits(candidate.Visits), float64(cx), float64(cy)+line_height, text.AlignCenter)
+	}
+
+	// Summary in the top margin
+	var summary string = fmt.Sprintf("Best %s %.1f%%, %s visits, %s sim/s (A: hide)",
+		environment.GtpVertex(best.Action, info.BoardHeight), 100*best.Winrate, FormatVisits(info.RootVisits), FormatVisits(int(info.SimulationsPerSecond)))
+	app.UIMetadata.DrawText(ebiten_image, summary, float64(app.UIMetadata.Margin.Left+app.UIMetadata.BoardSize/2), app.TopTextY(), text.AlignCenter)
+}
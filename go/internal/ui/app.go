@@ -1,13 +1,17 @@
 package ui
 
 import (
+	"bytes"
+	"sync"
+
 	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
 	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
 	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/render"
 	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/utils"
 	"github.com/hajimehoshi/ebiten/v2"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"golang.org/x/image/font/gofont/goregular"
 )
 
 // Locked types (LockedBool, LockedGame, LockedValue) moved to internal/utils.
@@ -36,10 +40,19 @@ type UIMetadata struct {
 	StoneRadiusScale                    float32
 	DescriptionBarHeight                float32
 	PassSquareSizeScale                 float32
+	SidePanelWidth                      float32 // Panel right of the board with the game graph
+	FontSize                            float32
+	FontSource                          *text.GoTextFaceSource
+	Scale                               float32     // Screen pixels per pixel of the reference layout
+	Reference                           *UIMetadata // Sizes of the layout at scale 1
 }
 
-func NewUI(window_title string, margin Margin, board_size, highlighted_intersections_radius_scale, stone_radius_scale, description_bar_height, pass_square_size_scale float32) *UIMetadata {
-	return &UIMetadata{
+func NewUI(window_title string, margin Margin, board_size, highlighted_intersections_radius_scale, stone_radius_scale, description_bar_height, pass_square_size_scale, side_panel_width, font_size float32) *UIMetadata {
+	font_source, err := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
+	if err != nil {
+		panic("NewUI: cannot load the font: " + err.Error())
+	}
+	var ui_metadata *UIMetadata = &UIMetadata{
 		WindowTitle:                         window_title,
 		Margin:                              margin,
 		BoardSize:                           board_size,
@@ -47,18 +60,37 @@ func NewUI(window_title string, margin Margin, board_size, highlighted_intersect
 		StoneRadiusScale:                    stone_radius_scale,
 		DescriptionBarHeight:                description_bar_height,
 		PassSquareSizeScale:                 pass_square_size_scale,
+		SidePanelWidth:                      side_panel_width,
+		FontSize:                            font_size,
+		FontSource:                          font_source,
+		Scale:                               1,
 	}
+	var reference UIMetadata = *ui_metadata
+	ui_metadata.Reference = &reference
+	return ui_metadata
 }
 
 type App struct {
 	MoveSearchInitiated chan bool // Channel to signal the start of move search (used for synchronization between the main thread and the MCTS goroutine)
 	IsThinking          *utils.LockedBool
 	IsPaused            *utils.LockedBool
+	IsReviewing         *utils.LockedBool // The moves of the live game wait while its positions are reviewed
+	ReviewMutex         sync.Mutex        // Held to start or stop a review, and to play a move only if none is running
+	Closed              *utils.LockedBool // Set when the game is left for a new one
 	BlackAgent          agents.Agent
 	WhiteAgent          agents.Agent
 	Game                *utils.LockedPointer[environment.Game]
+	InitialGame         *environment.Game // Position before the first move
+	Replay              *Replay           // nil while the live game is shown
 	UIMetadata          *UIMetadata
 	KeyStates           map[ebiten.Key]*utils.LockedPointer[KeyState]
+	LeftClick           *utils.LockedPointer[MouseButtonState]
+	IllegalClick        *utils.LockedPointer[IllegalClick]      // Last click of a human player on an illegal point
+	Evaluator           remote_trainer.PositionEvaluatorClient  // Evaluates the positions for the PUCT agents
+	Analysis            *utils.LockedPointer[agents.SearchInfo] // Last snapshot published by a searching agent
+	ShowAnalysis        *utils.LockedBool
+	Graph               *GameGraph
+	ShowMoveNumbers     *utils.LockedBool
 }
 
 func NewApp(black_agent, white_agent agents.Agent, game *environment.Game, ui_metadata *UIMetadata, key_list []ebiten.Key) *App {
@@ -66,11 +98,20 @@ func NewApp(black_agent, white_agent agents.Agent, game *environment.Game, ui_me
 		MoveSearchInitiated: make(chan bool, 1),
 		IsThinking:          utils.NewLockedBool(false), // Whether the current agent is thinking
 		IsPaused:            utils.NewLockedBool(false), // Whether the game is paused
+		IsReviewing:         utils.NewLockedBool(false),
+		Closed:              utils.NewLockedBool(false),
 		BlackAgent:          black_agent,
 		WhiteAgent:          white_agent,
 		Game:                utils.NewLockedPointer(game),
+		InitialGame:         game.DeepCopy(),
 		UIMetadata:          ui_metadata,
 		KeyStates:           make(map[ebiten.Key]*utils.LockedPointer[KeyState]),
+		LeftClick:           utils.NewLockedPointer(NewMouseButtonState(ebiten.MouseButtonLeft)),
+		IllegalClick:        utils.NewLockedPointer[IllegalClick](nil),
+		Analysis:            utils.NewLockedPointer[agents.SearchInfo](nil),
+		ShowAnalysis:        utils.NewLockedBool(true),
+		Graph:               NewGameGraph(),
+		ShowMoveNumbers:     utils.NewLockedBool(false),
 	}
 	for _, key := range key_list {
 		app.KeyStates[key] = utils.NewLockedPointer[KeyState](NewKeyState(key))
@@ -78,38 +119,47 @@ func NewApp(black_agent, white_agent agents.Agent, game *environment.Game, ui_me
 	return app
 }
 
-func InitializeApp() *App {
-
-	//establish UDS connection to the position evaluation server
-	conn, err := grpc.NewClient("unix:///tmp/position_evaluation.sock", grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		println("Error connecting to position evaluation server:", err.Error())
-		return nil
-	}
-
-	var _ remote_trainer.PositionEvaluatorClient = remote_trainer.NewPositionEvaluatorClient(conn)
-
-	var black_agent agents.Agent = agents.NewUctAgent(5000, 8, -0.7)
-	var white_agent agents.Agent = agents.NewUctAgent(5000, 8, -0.7)
-	var game *environment.Game = environment.NewGame(
-		9,   // height
-		9,   // width
-		6.5, // komi
-	)
-
-	var margin Margin = NewMargin(30, 30, 30, 30)
+func NewDefaultUI() *UIMetadata {
+	var margin Margin = NewMargin(48, 36, 36, 36)
 	const BoardSize float32 = 400
 	const WindowTitle string = "Go Game"
-	const HighlightedIntersectionsRadiusScale float32 = 0.1
-	const StoneRadiusScale float32 = 0.4
+	const HighlightedIntersectionsRadiusScale float32 = render.StarRadiusScale
+	const StoneRadiusScale float32 = render.StoneRadiusScale
 	const DescriptionBarHeight float32 = 50
 	const PassSquareSizeScale float32 = 0.8
-	var KeyList []ebiten.Key = []ebiten.Key{ebiten.KeySpace}
-
-	var ui_metadata *UIMetadata = NewUI(WindowTitle, margin, BoardSize, HighlightedIntersectionsRadiusScale, StoneRadiusScale, DescriptionBarHeight, PassSquareSizeScale)
-	var app *App = NewApp(black_agent, white_agent, game, ui_metadata, KeyList)
+	const SidePanelWidth float32 = 260
+	const FontSize float32 = 13
+	return NewUI(WindowTitle, margin, BoardSize, HighlightedIntersectionsRadiusScale, StoneRadiusScale, DescriptionBarHeight, PassSquareSizeScale, SidePanelWidth, FontSize)
+}
 
-	ebiten.SetWindowSize(app.WindowWidth(), app.WindowHeight())
-	ebiten.SetWindowTitle(WindowTitle)
+func InitializeApp(settings *GameSettings, ui_metadata *UIMetadata, client remote_trainer.PositionEvaluatorClient) *App {
+	var black_agent agents.Agent = NewPlayer(settings.Black, client)
+	var white_agent agents.Agent = NewPlayer(settings.White, client)
+	var KeyList []ebiten.Key = append([]ebiten.Key{ebiten.KeySpace, ebiten.KeyA}, ReplayKeys...)
+	var app *App = NewApp(black_agent, white_agent, settings.NewGame(), ui_metadata, KeyList)
+	app.Evaluator = client
+	app.WatchSearch(black_agent, settings.Size)
+	app.WatchSearch(white_agent, settings.Size)
+	app.EvaluatePositions([]*environment.Game{app.InitialGame})
 	return app
 }
+
+func (app *App) Close() {
+	// Stops the agents of a game that is left, a search already running finishes in the background
+	app.Closed.Set(true)
+	app.StopPondering()
+	for _, agent := range []agents.Agent{app.BlackAgent, app.WhiteAgent} {
+		if human, ok := agent.(*agents.HumanAgent); ok {
+			human.Play(environment.Resign{}) // Unblocks the move search goroutine
+		}
+	}
+	go func() {
+		// Once the move search goroutine is done, no other one can start
+		app.MoveSearchInitiated <- true
+		for _, agent := range []agents.Agent{app.BlackAgent, app.WhiteAgent} {
+			if closer, ok := agent.(agents.Closer); ok {
+				closer.Close()
+			}
+		}
+	}()
+}
@@ -1,93 +1,149 @@
 package ui
 
 import (
+	"fmt"
 	"image/color"
+	"time"
 
 	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/render"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
-	"golang.org/x/image/font"
-	"golang.org/x/image/font/basicfont"
 )
 
+func (app *App) Geometry() render.Geometry {
+	var board *environment.Board = app.Game.Get().Board
+	return render.NewGeometry(app.UIMetadata.Margin.Left, app.UIMetadata.Margin.Top, app.UIMetadata.BoardSize, board.Height, board.Width, app.UIMetadata.StoneRadiusScale, app.UIMetadata.HighlightedIntersectionsRadiusScale)
+}
+
 func (app *App) CellSize() float32 {
-	return app.UIMetadata.BoardSize / float32(app.Game.Get().Board.Width-1)
+	return app.Geometry().CellSize()
 }
 
 func (app *App) HighlightedIntersections() []environment.Position {
-	var highlighted_positions []environment.Position = make([]environment.Position, 0, 9)
-	switch app.Game.Get().Board.Height {
-	case 9:
-		highlighted_positions = []environment.Position{
-			environment.NewPosition(2, 2),
-			environment.NewPosition(2, 4),
-			environment.NewPosition(2, 6),
-			environment.NewPosition(4, 2),
-			environment.NewPosition(4, 4),
-			environment.NewPosition(4, 6),
-			environment.NewPosition(6, 2),
-			environment.NewPosition(6, 4),
-			environment.NewPosition(6, 6),
-		}
-	default:
-		panic("HighlightedIntersections: unsupported board size")
-	}
-	return highlighted_positions
+	// Star points, where the handicap stones go
+	return app.Geometry().StarPoints()
 }
 
 func (app *App) DrawBackground(ebiten_image *ebiten.Image) {
 	// Background
-	ebiten_image.Fill(color.RGBA{200, 170, 120, 255})
+	ebiten_image.Fill(render.BoardColor)
 }
 
 func (app *App) DrawGrid(ebiten_image *ebiten.Image) {
 
-	const stroke_width float32 = 2
+	var stroke_width float32 = app.UIMetadata.Px(2)
 	var line_color color.Color = color.Black
 	const antialias bool = true
+	var geometry render.Geometry = app.Geometry()
 
 	// Draw horizontal lines
-	for i := 0; i < app.Game.Get().Board.Height; i++ {
-		var x0, y0, x1, y1 float32 = app.UIMetadata.Margin.Left, app.UIMetadata.Margin.Top + app.CellSize()*float32(i), app.UIMetadata.BoardSize + app.UIMetadata.Margin.Left, app.UIMetadata.Margin.Top + app.CellSize()*float32(i)
-		vector.StrokeLine(ebiten_image, x0, y0, x1, y1, stroke_width, line_color, antialias)
+	for i := 0; i < geometry.Height; i++ {
+		var x0, y0 float32 = geometry.Point(i, 0)
+		vector.StrokeLine(ebiten_image, x0, y0, x0+geometry.GridWidth(), y0, stroke_width, line_color, antialias)
 	}
 
 	// Draw vertical lines
-	for j := 0; j < app.Game.Get().Board.Width; j++ {
-		var x0, y0, x1, y1 float32 = app.UIMetadata.Margin.Left + app.CellSize()*float32(j), app.UIMetadata.Margin.Top, app.UIMetadata.Margin.Left + app.CellSize()*float32(j), app.UIMetadata.BoardSize + app.UIMetadata.Margin.Top
-		vector.StrokeLine(ebiten_image, x0, y0, x1, y1, stroke_width, line_color, antialias)
+	for j := 0; j < geometry.Width; j++ {
+		var x0, y0 float32 = geometry.Point(0, j)
+		vector.StrokeLine(ebiten_image, x0, y0, x0, y0+geometry.GridHeight(), stroke_width, line_color, antialias)
 	}
 
 	// Draw highlighted intersections
-	var highlighted_positions []environment.Position = app.HighlightedIntersections()
-	for _, pos := range highlighted_positions {
-		var cx, cy float32 = app.UIMetadata.Margin.Left + app.CellSize()*float32(pos.Second), app.UIMetadata.Margin.Top + app.CellSize()*float32(pos.First)
-		var radius float32 = app.CellSize() * app.UIMetadata.HighlightedIntersectionsRadiusScale
-		vector.FillCircle(ebiten_image, cx, cy, radius, line_color, antialias)
+	for _, pos := range app.HighlightedIntersections() {
+		var cx, cy float32 = geometry.Point(pos.First, pos.Second)
+		vector.FillCircle(ebiten_image, cx, cy, geometry.StarRadius(), line_color, antialias)
 	}
+
+	app.DrawCoordinates(ebiten_image)
+}
+
+func (app *App) DrawCoordinates(ebiten_image *ebiten.Image) {
+	// GTP coordinates on the four edges
+	var offset float32 = app.UIMetadata.Px(15)
+	var geometry render.Geometry = app.Geometry()
+	var top, bottom float32 = geometry.Top - offset, geometry.Top + geometry.GridHeight() + offset
+	var left, right float32 = geometry.Left - offset, geometry.Left + geometry.GridWidth() + offset
+	for j := 0; j < geometry.Width; j++ {
+		x, _ := geometry.Point(0, j)
+		app.UIMetadata.DrawColoredText(ebiten_image, geometry.ColumnLabel(j), float64(x), float64(top), text.AlignCenter, color.Black)
+		app.UIMetadata.DrawColoredText(ebiten_image, geometry.ColumnLabel(j), float64(x), float64(bottom), text.AlignCenter, color.Black)
+	}
+	for i := 0; i < geometry.Height; i++ {
+		_, y := geometry.Point(i, 0)
+		app.UIMetadata.DrawColoredText(ebiten_image, geometry.RowLabel(i), float64(left), float64(y), text.AlignCenter, color.Black)
+		app.UIMetadata.DrawColoredText(ebiten_image, geometry.RowLabel(i), float64(right), float64(y), text.AlignCenter, color.Black)
+	}
+}
+
+func (app *App) DrawCaptures(ebiten_image *ebiten.Image) {
+	// Stones captured by each player, at the bottom of the side panel
+	var captures environment.Captures = app.Game.Get().Board.Captures
+	var x float64 = float64(app.UIMetadata.SidePanelLeft() + app.UIMetadata.SidePanelWidth/2)
+	var y float64 = float64(app.WindowHeight()) - float64(app.UIMetadata.DescriptionBarHeight)/2
+	app.UIMetadata.DrawText(ebiten_image, fmt.Sprintf("Captures: Black %d, White %d", captures.Black, captures.White), x, y-float64(app.UIMetadata.Px(8)), text.AlignCenter)
+	app.UIMetadata.DrawText(ebiten_image, "M: move numbers", x, y+float64(app.UIMetadata.Px(8)), text.AlignCenter)
+}
+
+func (app *App) TopTextY() float64 {
+	// Line of the help and analysis texts, above the column coordinates
+	return float64(app.UIMetadata.Margin.Top) / 3
 }
 
 func (app *App) DrawStones(ebiten_image *ebiten.Image) {
 	const antialias bool = true
-	for i := 0; i < app.Game.Get().Board.Height; i++ {
-		for j := 0; j < app.Game.Get().Board.Width; j++ {
-			var stone environment.Stone = app.Game.Get().Board.Matrix[i][j]
+	var geometry render.Geometry = app.Geometry()
+	var board *environment.Board = app.Game.Get().Board
+	for i := 0; i < geometry.Height; i++ {
+		for j := 0; j < geometry.Width; j++ {
+			var stone environment.Stone = board.Matrix[i][j]
 			if stone == environment.Empty {
 				continue
 			}
-			var cx, cy float32 = app.UIMetadata.Margin.Left + app.CellSize()*float32(j), app.UIMetadata.Margin.Top + app.CellSize()*float32(i)
-			var radius float32 = app.CellSize() * app.UIMetadata.StoneRadiusScale
-			var fill_color color.Color
-			switch stone {
-			case environment.Black:
-				fill_color = color.Black
-			case environment.White:
-				fill_color = color.White
-			}
-			vector.FillCircle(ebiten_image, cx, cy, radius, fill_color, antialias)
+			var cx, cy float32 = geometry.Point(i, j)
+			vector.FillCircle(ebiten_image, cx, cy, geometry.StoneRadius(), render.StoneColor(stone), antialias)
 		}
 	}
+	if app.ShowMoveNumbers.Get() {
+		app.DrawMoveNumbers(ebiten_image)
+	}
+	app.DrawLastMove(ebiten_image)
+	app.DrawKoPoints(ebiten_image)
+}
+
+func (app *App) DrawLastMove(ebiten_image *ebiten.Image) {
+	const antialias bool = true
+	var game *environment.Game = app.Game.Get()
+	stone, ok := render.LastStone(game, len(app.InitialGame.MoveHistory))
+	if !ok {
+		return
+	}
+	var geometry render.Geometry = app.Geometry()
+	var cx, cy float32 = geometry.Point(stone.I, stone.J)
+	var marker_color color.Color = render.StoneColor(game.Board.Matrix[stone.I][stone.J].Opponent())
+	var marker_radius float32 = render.LastMoveRadiusScale * geometry.StoneRadius()
+	if app.ShowMoveNumbers.Get() {
+		// Around the number instead, in red to tell it from the other numbers
+		marker_color, marker_radius = render.MarkerColor, render.NumberedLastMoveRadiusScale*geometry.StoneRadius()
+	}
+	vector.StrokeCircle(ebiten_image, cx, cy, marker_radius, app.UIMetadata.Px(2), marker_color, antialias)
+}
+
+func (app *App) DrawKoPoints(ebiten_image *ebiten.Image) {
+	// Square on the points the player to move cannot retake yet
+	const antialias bool = true
+	var game *environment.Game = app.Game.Get()
+	if game.IsTerminal() {
+		return
+	}
+	var geometry render.Geometry = app.Geometry()
+	var half_side float32 = render.KoSquareScale * geometry.StoneRadius()
+	for _, pos := range game.KoPoints() {
+		var cx, cy float32 = geometry.Point(pos.First, pos.Second)
+		vector.FillRect(ebiten_image, cx-half_side, cy-half_side, 2*half_side, 2*half_side, render.BoardColor, antialias)
+		vector.StrokeRect(ebiten_image, cx-half_side, cy-half_side, 2*half_side, 2*half_side, app.UIMetadata.Px(2), render.MarkerColor, antialias)
+	}
 }
 
 func (app *App) DescriptionBarWidth() float32 {
@@ -101,14 +157,7 @@ func (app *App) DescriptionBarCenter() (float64, float64) {
 func (app *App) DrawDescriptionBar(ebiten_image *ebiten.Image) {
 
 	// Write in the bottom margin
-	const antialias bool = true
-	var font_face font.Face = basicfont.Face7x13
-	var text_face text.Face = text.NewGoXFace(font_face)
-	var draw_options *text.DrawOptions = &text.DrawOptions{}
 	var text_center_x, text_center_y float64 = app.DescriptionBarCenter()
-	draw_options.GeoM.Translate(text_center_x, text_center_y)
-	draw_options.PrimaryAlign = text.AlignCenter
-	draw_options.SecondaryAlign = text.AlignCenter
 
 	// Draw description text
 	var description_text string
@@ -116,11 +165,11 @@ func (app *App) DrawDescriptionBar(ebiten_image *ebiten.Image) {
 		var winner environment.Stone = app.Game.Get().GetWinner()
 		switch winner {
 		case environment.Empty:
-			description_text = "Game over: Draw"
+			description_text = "Game over: Draw (R: replay, N: new game)"
 		case environment.Black:
-			description_text = "Game over: Black wins"
+			description_text = "Game over: Black wins (R: replay, N: new game)"
 		case environment.White:
-			description_text = "Game over: White wins"
+			description_text = "Game over: White wins (R: replay, N: new game)"
 		}
 	} else {
 		switch app.Game.Get().Board.CurrentPlayer {
@@ -129,14 +178,19 @@ func (app *App) DrawDescriptionBar(ebiten_image *ebiten.Image) {
 		case environment.White:
 			description_text = "White"
 		}
-		switch app.IsThinking.Get() {
-		case true:
+		switch {
+		case app.CurrentHuman() != nil:
+			description_text += " to play"
+			if click := app.IllegalClick.Get(); click != nil && time.Since(click.Time) < IllegalClickDuration {
+				description_text += ", illegal move!"
+			}
+		case app.IsThinking.Get():
 			description_text += " is thinking..."
 		default:
 			description_text += " is ready to play!"
 		}
 	}
-	text.Draw(ebiten_image, description_text, text_face, draw_options)
+	app.UIMetadata.DrawText(ebiten_image, description_text, text_center_x, text_center_y, text.AlignCenter)
 }
 
 func (app *App) PassSquareMarginScale() float32 {
@@ -167,8 +221,8 @@ func (app *App) DrawPassSquare(ebiten_image *ebiten.Image) {
 
 	var square_size float32 = app.PassSquareSize()
 	// background colors for the pass squares
-	var not_passed_background_color color.Color = color.RGBA{255, 0, 0, 50}
-	var passed_background_color color.Color = color.RGBA{0, 255, 0, 50}
+	var not_passed_background_color color.Color = color.NRGBA{255, 0, 0, 50}
+	var passed_background_color color.Color = color.NRGBA{0, 255, 0, 50}
 
 	//draw square for black
 	var x, y float32 = app.PassSquarePosition(environment.Black)
@@ -177,7 +231,7 @@ func (app *App) DrawPassSquare(ebiten_image *ebiten.Image) {
 	} else {
 		vector.FillRect(ebiten_image, x, y, square_size, square_size, not_passed_background_color, true)
 	}
-	vector.StrokeRect(ebiten_image, x, y, square_size, square_size, 2, color.Black, true)
+	vector.StrokeRect(ebiten_image, x, y, square_size, square_size, app.UIMetadata.Px(2), color.Black, true)
 
 	//draw square for white
 	x, y = app.PassSquarePosition(environment.White)
@@ -186,13 +240,22 @@ func (app *App) DrawPassSquare(ebiten_image *ebiten.Image) {
 	} else {
 		vector.FillRect(ebiten_image, x, y, square_size, square_size, not_passed_background_color, true)
 	}
-	vector.StrokeRect(ebiten_image, x, y, square_size, square_size, 2, color.White, true)
+	vector.StrokeRect(ebiten_image, x, y, square_size, square_size, app.UIMetadata.Px(2), color.White, true)
 }
 
 func (app *App) Draw(ebiten_image *ebiten.Image) {
 	app.DrawBackground(ebiten_image)
 	app.DrawGrid(ebiten_image)
 	app.DrawStones(ebiten_image)
+	app.DrawGraph(ebiten_image)
+	app.DrawCaptures(ebiten_image)
+	if app.Replay != nil {
+		app.DrawReplayBar(ebiten_image)
+		app.DrawPassSquare(ebiten_image)
+		return
+	}
+	app.DrawAnalysis(ebiten_image)
+	app.DrawHumanInput(ebiten_image)
 	app.DrawDescriptionBar(ebiten_image)
 	app.DrawPassSquare(ebiten_image)
 }
@@ -0,0 +1,220 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"math"
+	"sync"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/features"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/utils"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Evaluation of the position after a move, from the point of view of black
+type GraphPoint struct {
+	Winrate    float64 // In [0, 1]
+	Searched   bool    // Whether the winrate is the root value of a search, and not the evaluator's guess
+	ScoreLead  float64
+	HasScore   bool
+	HasWinrate bool
+}
+
+// Evaluations of the positions of a game, indexed by their number of moves
+type GameGraph struct {
+	Points map[int]GraphPoint
+	Mutex  sync.Mutex
+}
+
+// Constructor
+func NewGameGraph() *GameGraph {
+	return &GameGraph{Points: make(map[int]GraphPoint)}
+}
+
+// Methods
+func (graph *GameGraph) AddSearch(info agents.SearchInfo) {
+	// The root value of a search is more accurate than the evaluator, it replaces its winrate
+	if info.RootVisits == 0 {
+		return
+	}
+	var winrate float64 = (info.RootValue + 1) / 2
+	if info.Player == environment.White {
+		winrate = 1 - winrate
+	}
+	graph.Mutex.Lock()
+	defer graph.Mutex.Unlock()
+	var point GraphPoint = graph.Points[info.MoveNumber]
+	point.Winrate, point.Searched, point.HasWinrate = winrate, true, true
+	graph.Points[info.MoveNumber] = point
+}
+
+func (graph *GameGraph) AddEvaluation(game *environment.Game, response *remote_trainer.EvaluatePositionResponse) {
+	var sign float64 = 1
+	if game.Board.CurrentPlayer == environment.White {
+		sign = -1
+	}
+	graph.Mutex.Lock()
+	defer graph.Mutex.Unlock()
+	var point GraphPoint = graph.Points[len(game.MoveHistory)]
+	if !point.Searched {
+		point.Winrate, point.HasWinrate = (1+sign*max(-1, min(1, float64(response.Value))))/2, true
+	}
+	if response.Score != nil {
+		point.ScoreLead, point.HasScore = sign*float64(*response.Score), true
+	}
+	graph.Points[len(game.MoveHistory)] = point
+}
+
+func (graph *GameGraph) Snapshot() map[int]GraphPoint {
+	graph.Mutex.Lock()
+	defer graph.Mutex.Unlock()
+	var points map[int]GraphPoint = make(map[int]GraphPoint, len(graph.Points))
+	for move, point := range graph.Points {
+		points[move] = point
+	}
+	return points
+}
+
+func (app *App) EvaluatePositions(games []*environment.Game) {
+	// Asks the evaluator for the value and score of the positions, in the background
+	if app.Evaluator == nil {
+		return
+	}
+	var copies []*environment.Game = make([]*environment.Game, len(games))
+	for i, game := range games {
+		copies[i] = game.DeepCopy()
+	}
+	go func() {
+		for _, game := range copies {
+			if app.Closed.Get() || game.IsTerminal() {
+				continue
+			}
+			var request *remote_trainer.EvaluatePositionRequest = &remote_trainer.EvaluatePositionRequest{
+				Height:    int32(game.Board.Height),
+				Width:     int32(game.Board.Width),
+				NumPlanes: features.NumPlanes,
+				Planes:    features.Encode(game),
+				LegalMask: features.LegalMask(game),
+			}
+			response, err := app.Evaluator.EvaluatePosition(context.Background(), request)
+			if err != nil {
+				println("Error evaluating position:", err.Error())
+				return
+			}
+			app.Graph.AddEvaluation(game, response)
+		}
+	}()
+}
+
+func (ui_metadata *UIMetadata) SidePanelLeft() float32 {
+	return ui_metadata.Margin.Left + ui_metadata.BoardSize + ui_metadata.Margin.Right
+}
+
+func (app *App) GraphRect() (float32, float32, float32, float32) {
+	// Plot area of the side panel, the top and bottom leave room for the legends, the captures are below
+	var padding float32 = app.UIMetadata.Px(20)
+	var left float32 = app.UIMetadata.SidePanelLeft() + padding
+	var top float32 = app.UIMetadata.Margin.Top + padding
+	var width float32 = app.UIMetadata.SidePanelWidth - 2*padding
+	var height float32 = app.UIMetadata.Margin.Top + app.UIMetadata.BoardSize + app.UIMetadata.Margin.Bottom - padding - top
+	return left, top, width, height
+}
+
+func (app *App) GraphMoves() (int, int, int) {
+	// First and last move numbers plotted, and the move shown on the board
+	var first int = len(app.InitialGame.MoveHistory)
+	if app.Replay != nil {
+		var positions []*environment.Game = app.Replay.Positions
+		return first, len(positions[len(positions)-1].MoveHistory), len(app.Game.Get().MoveHistory)
+	}
+	var current int = len(app.Game.Get().MoveHistory)
+	return first, current, current
+}
+
+func (app *App) HandleGraphClick() {
+	// Clicking on the graph shows the position of that move in review mode, a live game waits until the review is left
+	if !app.LeftClick.Get().JustPressed() {
+		return
+	}
+	x, y := ebiten.CursorPosition()
+	var left, top, width, height float32 = app.GraphRect()
+	if !IsInside(x, y, left-app.UIMetadata.Px(5), top, width+app.UIMetadata.Px(10), height) {
+		return
+	}
+	var first, last, _ int = app.GraphMoves()
+	var fraction float32 = max(0, min(1, (float32(x)-left)/width))
+	var move int = int(fraction*float32(max(1, last-first))+0.5) + first
+	if app.Replay == nil {
+		app.StartReplay()
+	}
+	app.ShowMove(app.Replay.IndexOfMove(move))
+}
+
+func (app *App) DrawGraph(ebiten_image *ebiten.Image) {
+	const antialias bool = true
+	var panel_left float32 = app.UIMetadata.SidePanelLeft()
+	vector.FillRect(ebiten_image, panel_left, 0, app.UIMetadata.SidePanelWidth, float32(app.WindowHeight()), color.RGBA{60, 45, 30, 255}, antialias)
+	var left, top, width, height float32 = app.GraphRect()
+	vector.FillRect(ebiten_image, left, top, width, height, color.RGBA{40, 30, 20, 255}, antialias)
+	vector.StrokeLine(ebiten_image, left, top+height/2, left+width, top+height/2, app.UIMetadata.Px(1), color.RGBA{150, 150, 150, 255}, antialias)
+
+	var first, last, current int = app.GraphMoves()
+	var points map[int]GraphPoint = app.Graph.Snapshot()
+	var max_lead float64 = 10
+	for _, point := range points {
+		if point.HasScore {
+			max_lead = max(max_lead, math.Abs(point.ScoreLead))
+		}
+	}
+	var move_x func(move int) float32 = func(move int) float32 {
+		return left + width*float32(move-first)/float32(max(1, last-first))
+	}
+
+	// Move shown on the board
+	vector.StrokeLine(ebiten_image, move_x(current), top, move_x(current), top+height, app.UIMetadata.Px(2), color.RGBA{220, 30, 30, 255}, antialias)
+
+	// Score lead, scaled to the largest lead of the game, then black's winrate on top
+	var score_color color.Color = color.RGBA{240, 180, 60, 255}
+	var winrate_color color.Color = color.RGBA{80, 220, 255, 255}
+	var previous_score, previous_winrate *utils.Pair[float32, float32]
+	for move := first; move <= last; move++ {
+		point, ok := points[move]
+		if !ok {
+			continue
+		}
+		var x float32 = move_x(move)
+		if point.HasScore {
+			var y float32 = top + height/2 - float32(point.ScoreLead/max_lead)*height/2
+			if previous_score != nil {
+				vector.StrokeLine(ebiten_image, previous_score.First, previous_score.Second, x, y, app.UIMetadata.Px(1.5), score_color, antialias)
+			}
+			previous_score = &utils.Pair[float32, float32]{First: x, Second: y}
+		}
+		if point.HasWinrate {
+			var y float32 = top + height*float32(1-point.Winrate)
+			if previous_winrate != nil {
+				vector.StrokeLine(ebiten_image, previous_winrate.First, previous_winrate.Second, x, y, app.UIMetadata.Px(2), winrate_color, antialias)
+			}
+			previous_winrate = &utils.Pair[float32, float32]{First: x, Second: y}
+		}
+	}
+
+	// Legend and values of the move shown
+	var center_x float64 = float64(left + width/2)
+	var legend string = "Black winrate"
+	if point, ok := points[current]; ok && point.HasWinrate {
+		legend += fmt.Sprintf(" %.1f%%", 100*point.Winrate)
+	}
+	app.UIMetadata.DrawColoredText(ebiten_image, legend, center_x, float64(top-app.UIMetadata.Px(12)), text.AlignCenter, winrate_color)
+	var score_legend string = fmt.Sprintf("Score lead (+-%.0f)", max_lead)
+	if point, ok := points[current]; ok && point.HasScore {
+		score_legend = fmt.Sprintf("Score lead %+.1f (+-%.0f)", point.ScoreLead, max_lead)
+	}
+	app.UIMetadata.DrawColoredText(ebiten_image, score_legend, center_x, float64(top+height+app.UIMetadata.Px(12)), text.AlignCenter, score_color)
+}
@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"image/color"
+	"time"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const IllegalClickDuration time.Duration = 800 * time.Millisecond
+
+// Click of a human player on a point where they cannot play
+type IllegalClick struct {
+	Position environment.Position
+	Time     time.Time
+}
+
+func (app *App) CurrentHuman() *agents.HumanAgent {
+	// Human player whose move is awaited, nil if it is a bot's turn
+	var current_agent agents.Agent = app.BlackAgent
+	if app.Game.Get().Board.CurrentPlayer == environment.White {
+		current_agent = app.WhiteAgent
+	}
+	if human, ok := current_agent.(*agents.HumanAgent); ok && human.Waiting.Get() {
+		return human
+	}
+	return nil
+}
+
+func (app *App) IntersectionAt(x, y int) (environment.Position, bool) {
+	return app.Geometry().IntersectionAt(float32(x), float32(y))
+}
+
+func IsInside(x, y int, left, top, width, height float32) bool {
+	return float32(x) >= left && float32(x) <= left+width && float32(y) >= top && float32(y) <= top+height
+}
+
+func (app *App) ResignButtonPosition() (float32, float32) {
+	// Left end of the description bar, the pass squares are at the right end
+	_, y := app.PassSquarePosition(environment.Black)
+	return app.PassSquareMargin(), y
+}
+
+func (app *App) ResignButtonSize() (float32, float32) {
+	return 1.5 * app.PassSquareSize(), app.PassSquareSize()
+}
+
+func (app *App) HandleHumanInput() {
+	var human *agents.HumanAgent = app.CurrentHuman()
+	if human == nil || !app.LeftClick.Get().JustPressed() {
+		return
+	}
+	var game *environment.Game = app.Game.Get()
+	x, y := ebiten.CursorPosition()
+
+	var pass_x, pass_y float32 = app.PassSquarePosition(game.Board.CurrentPlayer)
+	var resign_x, resign_y float32 = app.ResignButtonPosition()
+	var resign_width, resign_height float32 = app.ResignButtonSize()
+	switch {
+	case IsInside(x, y, pass_x, pass_y, app.PassSquareSize(), app.PassSquareSize()):
+		human.Play(environment.Pass{})
+	case IsInside(x, y, resign_x, resign_y, resign_width, resign_height):
+		human.Play(environment.Resign{})
+	default:
+		position, ok := app.IntersectionAt(x, y)
+		if !ok {
+			return
+		}
+		if !game.IsLegalAction(position.First, position.Second) {
+			app.IllegalClick.Set(&IllegalClick{Position: position, Time: time.Now()})
+			return
+		}
+		human.Play(environment.PutStone{I: position.First, J: position.Second})
+	}
+}
+
+func (app *App) DrawHumanInput(ebiten_image *ebiten.Image) {
+	const antialias bool = true
+	var game *environment.Game = app.Game.Get()
+	var radius float32 = app.CellSize() * app.UIMetadata.StoneRadiusScale
+
+	// Cross on the last illegal point clicked, for a short while
+	if click := app.IllegalClick.Get(); click != nil && time.Since(click.Time) < IllegalClickDuration {
+		var cx, cy float32 = app.UIMetadata.Margin.Left + app.CellSize()*float32(click.Position.Second), app.UIMetadata.Margin.Top + app.CellSize()*float32(click.Position.First)
+		var arm float32 = 0.6 * radius
+		var red color.Color = color.RGBA{220, 30, 30, 255}
+		vector.StrokeLine(ebiten_image, cx-arm, cy-arm, cx+arm, cy+arm, app.UIMetadata.Px(3), red, antialias)
+		vector.StrokeLine(ebiten_image, cx-arm, cy+arm, cx+arm, cy-arm, app.UIMetadata.Px(3), red, antialias)
+	}
+
+	if app.CurrentHuman() == nil {
+		return
+	}
+
+	// Ghost stone under the cursor, red where the move is illegal
+	x, y := ebiten.CursorPosition()
+	if position, ok := app.IntersectionAt(x, y); ok && game.Board.Matrix[position.First][position.Second] == environment.Empty {
+		var cx, cy float32 = app.UIMetadata.Margin.Left + app.CellSize()*float32(position.Second), app.UIMetadata.Margin.Top + app.CellSize()*float32(position.First)
+		var ghost_color color.Color = color.NRGBA{0, 0, 0, 110}
+		if game.Board.CurrentPlayer == environment.White {
+			ghost_color = color.NRGBA{255, 255, 255, 140}
+		}
+		if !game.IsLegalAction(position.First, position.Second) {
+			ghost_color = color.NRGBA{220, 30, 30, 90}
+		}
+		vector.FillCircle(ebiten_image, cx, cy, radius, ghost_color, antialias)
+	}
+
+	// Resign button, the pass squares double as pass buttons
+	var resign_x, resign_y float32 = app.ResignButtonPosition()
+	var resign_width, resign_height float32 = app.ResignButtonSize()
+	vector.FillRect(ebiten_image, resign_x, resign_y, resign_width, resign_height, color.NRGBA{255, 255, 255, 60}, antialias)
+	vector.StrokeRect(ebiten_image, resign_x, resign_y, resign_width, resign_height, app.UIMetadata.Px(2), color.Black, antialias)
+	app.UIMetadata.DrawText(ebiten_image, "Resign", float64(resign_x+resign_width/2), float64(resign_y+resign_height/2), text.AlignCenter)
+	var pass_x, pass_y float32 = app.PassSquarePosition(game.Board.CurrentPlayer)
+	app.UIMetadata.DrawText(ebiten_image, "Pass", float64(pass_x+app.PassSquareSize()/2), float64(pass_y+app.PassSquareSize()/2), text.AlignCenter)
+}
@@ -30,3 +30,27 @@ type InputState interface {
 	JustPressed() bool
 	IsPressed() bool
 }
+
+type MouseButtonState struct {
+	Button     ebiten.MouseButton
+	WasPressed bool
+	IsPressed  bool
+}
+
+func NewMouseButtonState(button ebiten.MouseButton) *MouseButtonState {
+	// A button held while the state is created, like the click that opened a screen, is not a new press
+	return &MouseButtonState{
+		Button:     button,
+		WasPressed: false,
+		IsPressed:  ebiten.IsMouseButtonPressed(button),
+	}
+}
+
+func (ms *MouseButtonState) Update() {
+	ms.WasPressed = ms.IsPressed
+	ms.IsPressed = ebiten.IsMouseButtonPressed(ms.Button)
+}
+
+func (ms *MouseButtonState) JustPressed() bool {
+	return ms.IsPressed && !ms.WasPressed
+}
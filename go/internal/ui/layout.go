@@ -1,13 +1,79 @@
 package ui
 
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+func (ui_metadata *UIMetadata) WindowHeight() int {
+	return int(ui_metadata.Margin.Top + ui_metadata.BoardSize + ui_metadata.Margin.Bottom + ui_metadata.DescriptionBarHeight)
+}
+
+func (ui_metadata *UIMetadata) WindowWidth() int {
+	return int(ui_metadata.Margin.Left + ui_metadata.BoardSize + ui_metadata.Margin.Right + ui_metadata.SidePanelWidth)
+}
+
+func (ui_metadata *UIMetadata) Px(length float32) float32 {
+	// Length of the reference layout in screen pixels
+	return ui_metadata.Scale * length
+}
+
+func (ui_metadata *UIMetadata) Resize(width, height int) {
+	// Scales the reference layout to fit the screen, the board takes the space left by the bars and is centered in it
+	var reference *UIMetadata = ui_metadata.Reference
+	var scale float32 = min(float32(width)/float32(reference.WindowWidth()), float32(height)/float32(reference.WindowHeight()))
+	if scale <= 0 {
+		return
+	}
+	ui_metadata.Scale = scale
+	ui_metadata.DescriptionBarHeight = scale * reference.DescriptionBarHeight
+	ui_metadata.SidePanelWidth = scale * reference.SidePanelWidth
+	var margin Margin = NewMargin(scale*reference.Margin.Top, scale*reference.Margin.Bottom, scale*reference.Margin.Left, scale*reference.Margin.Right)
+	var available_width float32 = float32(width) - ui_metadata.SidePanelWidth - margin.Left - margin.Right
+	var available_height float32 = float32(height) - ui_metadata.DescriptionBarHeight - margin.Top - margin.Bottom
+	ui_metadata.BoardSize = min(available_width, available_height)
+	margin.Left += (available_width - ui_metadata.BoardSize) / 2
+	margin.Right += (available_width - ui_metadata.BoardSize) / 2
+	margin.Top += (available_height - ui_metadata.BoardSize) / 2
+	margin.Bottom += (available_height - ui_metadata.BoardSize) / 2
+	ui_metadata.Margin = margin
+}
+
+func (ui_metadata *UIMetadata) ScreenSize(outside_width, outside_height int) (int, int) {
+	// Size of the screen in device pixels, so that the layout stays sharp on HiDPI displays
+	var device_scale float64 = ebiten.Monitor().DeviceScaleFactor()
+	var width, height int = int(float64(outside_width) * device_scale), int(float64(outside_height) * device_scale)
+	ui_metadata.Resize(width, height)
+	return width, height
+}
+
+func (ui_metadata *UIMetadata) FontFace() text.Face {
+	return &text.GoTextFace{Source: ui_metadata.FontSource, Size: float64(ui_metadata.Px(ui_metadata.FontSize))}
+}
+
+func (ui_metadata *UIMetadata) DrawColoredText(ebiten_image *ebiten.Image, message string, x, y float64, align text.Align, text_color color.Color) {
+	var draw_options *text.DrawOptions = &text.DrawOptions{}
+	draw_options.GeoM.Translate(x, y)
+	draw_options.PrimaryAlign = align
+	draw_options.SecondaryAlign = text.AlignCenter
+	draw_options.ColorScale.ScaleWithColor(text_color)
+	text.Draw(ebiten_image, message, ui_metadata.FontFace(), draw_options)
+}
+
+func (ui_metadata *UIMetadata) DrawText(ebiten_image *ebiten.Image, message string, x, y float64, align text.Align) {
+	ui_metadata.DrawColoredText(ebiten_image, message, x, y, align, color.White)
+}
+
 func (app *App) WindowHeight() int {
-	return int(app.UIMetadata.Margin.Top + app.UIMetadata.BoardSize + app.UIMetadata.Margin.Bottom + app.UIMetadata.DescriptionBarHeight)
+	return app.UIMetadata.WindowHeight()
 }
 
 func (app *App) WindowWidth() int {
-	return int(app.UIMetadata.Margin.Left + app.UIMetadata.BoardSize + app.UIMetadata.Margin.Right)
+	return app.UIMetadata.WindowWidth()
 }
 
 func (app *App) Layout(outside_width, outside_height int) (int, int) {
-	return app.WindowWidth(), app.WindowHeight()
+	return app.UIMetadata.ScreenSize(outside_width, outside_height)
 }
@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/render"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/sgf"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Replay of a game, move by move
+type Replay struct {
+	Positions []*environment.Game // Initial position, then the position after each move
+	Index     int                 // Position shown
+	Result    string
+	LiveGame  *environment.Game // Game to go back to when leaving the replay, nil if the replay cannot be left
+	Dragging  bool              // Whether the slider is being dragged
+}
+
+var ReplayKeys []ebiten.Key = []ebiten.Key{ebiten.KeyLeft, ebiten.KeyRight, ebiten.KeyUp, ebiten.KeyDown, ebiten.KeyHome, ebiten.KeyEnd, ebiten.KeyR, ebiten.KeyEscape, ebiten.KeyM}
+
+func Positions(initial *environment.Game, actions []environment.Action) []*environment.Game {
+	var game *environment.Game = initial.DeepCopy()
+	var positions []*environment.Game = []*environment.Game{game.DeepCopy()}
+	for _, action := range actions {
+		game.PlayAction(action)
+		positions = append(positions, game.DeepCopy())
+	}
+	return positions
+}
+
+func NewReplayApp(record *sgf.Record, ui_metadata *UIMetadata) (*App, error) {
+	// App showing a recorded game, without players
+	positions, err := record.Positions()
+	if err != nil {
+		return nil, err
+	}
+	var app *App = NewApp(nil, nil, positions[len(positions)-1], ui_metadata, ReplayKeys)
+	app.InitialGame = positions[0]
+	app.Replay = &Replay{Positions: positions, Index: 0, Result: record.Result}
+	app.ShowMoveNumbers.Set(true)
+	app.Game.Set(positions[0])
+	return app, nil
+}
+
+func (app *App) StartReplay() {
+	// Replays the moves of the game played in the app, from the start
+	app.ReviewMutex.Lock()
+	defer app.ReviewMutex.Unlock()
+	var live_game *environment.Game = app.Game.Get()
+	var result string
+	if live_game.IsTerminal() {
+		result = sgf.Result(live_game)
+	}
+	var positions []*environment.Game = Positions(app.InitialGame, live_game.MoveHistory[len(app.InitialGame.MoveHistory):])
+	app.Replay = &Replay{Positions: positions, Index: 0, Result: result, LiveGame: live_game}
+	app.IsReviewing.Set(true)
+	app.ShowMoveNumbers.Set(true)
+	app.Game.Set(positions[0])
+}
+
+func (app *App) StopReplay() {
+	if app.Replay == nil || app.Replay.LiveGame == nil {
+		return
+	}
+	app.ReviewMutex.Lock()
+	defer app.ReviewMutex.Unlock()
+	app.Game.Set(app.Replay.LiveGame)
+	app.Replay = nil
+	app.IsReviewing.Set(false)
+}
+
+func (replay *Replay) IndexOfMove(move_number int) int {
+	// Last position with at most move_number moves played, the passes inserted by an SGF record count as moves
+	var index int = 0
+	for position_idx, position := range replay.Positions {
+		if len(position.MoveHistory) <= move_number {
+			index = position_idx
+		}
+	}
+	return index
+}
+
+func (app *App) ShowMove(index int) {
+	var replay *Replay = app.Replay
+	replay.Index = max(0, min(len(replay.Positions)-1, index))
+	app.Game.Set(replay.Positions[replay.Index])
+}
+
+func (app *App) SliderRect() (float32, float32, float32, float32) {
+	// Track of the slider, at the top of the description bar, left of the pass squares
+	var left float32 = app.UIMetadata.Margin.Left
+	var top float32 = app.UIMetadata.Margin.Top + app.UIMetadata.BoardSize + app.UIMetadata.Margin.Bottom + app.UIMetadata.Px(8)
+	var width float32 = app.DescriptionBarWidth() - left - app.PassSquareMargin()
+	return left, top, width, app.UIMetadata.Px(10)
+}
+
+func (app *App) UpdateReplay() {
+	var replay *Replay = app.Replay
+	var key_steps map[ebiten.Key]int = map[ebiten.Key]int{
+		ebiten.KeyLeft:  -1,
+		ebiten.KeyRight: 1,
+		ebiten.KeyUp:    -10,
+		ebiten.KeyDown:  10,
+		ebiten.KeyHome:  -len(replay.Positions),
+		ebiten.KeyEnd:   len(replay.Positions),
+	}
+	for key, step := range key_steps {
+		if app.KeyStates[key].Get().JustPressed() {
+			app.ShowMove(replay.Index + step)
+		}
+	}
+	if app.KeyStates[ebiten.KeyEscape].Get().JustPressed() || app.KeyStates[ebiten.KeyR].Get().JustPressed() {
+		app.StopReplay()
+		return
+	}
+
+	// Clicking on the slider jumps to the move, holding the button drags it
+	x, y := ebiten.CursorPosition()
+	var left, top, width, height float32 = app.SliderRect()
+	if app.LeftClick.Get().JustPressed() && IsInside(x, y, left-height/2, top-height/2, width+height, 2*height) {
+		replay.Dragging = true
+	}
+	if !app.LeftClick.Get().IsPressed {
+		replay.Dragging = false
+	}
+	if replay.Dragging {
+		var fraction float32 = max(0, min(1, (float32(x)-left)/width))
+		app.ShowMove(int(fraction*float32(len(replay.Positions)-1) + 0.5))
+	}
+}
+
+func (app *App) DrawMoveNumbers(ebiten_image *ebiten.Image) {
+	var game *environment.Game = app.Game.Get()
+	var numbers [][]int = render.MoveNumbers(game, len(app.InitialGame.MoveHistory))
+	for i := range numbers {
+		for j, number := range numbers[i] {
+			var stone environment.Stone = game.Board.Matrix[i][j]
+			if stone == environment.Empty || number == 0 {
+				continue
+			}
+			var cx, cy float32 = app.Geometry().Point(i, j)
+			app.UIMetadata.DrawColoredText(ebiten_image, fmt.Sprint(number), float64(cx), float64(cy), text.AlignCenter, render.StoneColor(stone.Opponent()))
+		}
+	}
+}
+
+func (app *App) DrawReplayBar(ebiten_image *ebiten.Image) {
+	const antialias bool = true
+	var replay *Replay = app.Replay
+	var left, top, width, height float32 = app.SliderRect()
+	vector.FillRect(ebiten_image, left, top+height/2-app.UIMetadata.Px(2), width, app.UIMetadata.Px(4), color.RGBA{90, 60, 30, 255}, antialias)
+	var moves int = len(replay.Positions) - 1
+	var knob_x float32 = left
+	if moves > 0 {
+		knob_x += width * float32(replay.Index) / float32(moves)
+	}
+	vector.FillCircle(ebiten_image, knob_x, top+height/2, height/2+app.UIMetadata.Px(2), color.Black, antialias)
+
+	var description string = fmt.Sprintf("Move %d/%d", replay.Index, moves)
+	if replay.Index > 0 {
+		var game *environment.Game = replay.Positions[replay.Index]
+		var player string = "W"
+		if game.Board.CurrentPlayer == environment.White {
+			player = "B" // The player who just moved
+		}
+		description += fmt.Sprintf(": %s %s", player, environment.GtpVertex(game.MoveHistory[len(game.MoveHistory)-1], game.Board.Height))
+	}
+	if replay.Index == moves && replay.Result != "" {
+		description += ", " + replay.Result
+	}
+	text_x, _ := app.DescriptionBarCenter()
+	app.UIMetadata.DrawText(ebiten_image, description, text_x, float64(top+height+app.UIMetadata.Px(16)), text.AlignCenter)
+
+	var help string = "Left/Right: move  Up/Down: 10 moves  Home/End  M: numbers"
+	if replay.LiveGame != nil {
+		help += "  Esc: leave"
+	}
+	app.UIMetadata.DrawText(ebiten_image, help, float64(app.UIMetadata.Margin.Left+app.UIMetadata.BoardSize/2), app.TopTextY(), text.AlignCenter)
+}
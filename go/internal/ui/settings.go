@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+)
+
+var PlayerKinds []string = []string{"human", "random", "uct", "puct"}
+
+type PlayerSettings struct {
+	Kind        string // One of PlayerKinds
+	Simulations int    // Search settings of the uct and puct players
+	Routines    int
+}
+
+type GameSettings struct {
+	Black    PlayerSettings
+	White    PlayerSettings
+	Size     int
+	Komi     float64
+	Handicap int
+	Rules    environment.Ruleset
+}
+
+func NewDefaultGameSettings() *GameSettings {
+	return &GameSettings{
+		Black:    PlayerSettings{Kind: "human", Simulations: 5000, Routines: 8},
+		White:    PlayerSettings{Kind: "uct", Simulations: 5000, Routines: 8},
+		Size:     9,
+		Komi:     6.5,
+		Handicap: 0,
+		Rules:    environment.AreaScoring,
+	}
+}
+
+func NewPlayer(player PlayerSettings, client remote_trainer.PositionEvaluatorClient) agents.Agent {
+	var agent *agents.MctsAgent
+	switch player.Kind {
+	case "human":
+		return agents.NewHumanAgent()
+	case "random":
+		return agents.NewRandomAgent()
+	case "uct":
+		agent = agents.NewUctAgent(player.Simulations, player.Routines, -0.7)
+	case "puct":
+		agent = agents.NewPuctAgent(player.Simulations, player.Routines, -0.7, client)
+	default:
+		panic("NewPlayer: unknown player kind " + player.Kind)
+	}
+	// Ponders with half of its routines while the opponent thinks
+	agent.SetPondering(max(1, player.Routines/2), 200000)
+	// Save simulations on moves that are already decided
+	agent.EarlyStopping = true
+	return agent
+}
+
+func (settings *GameSettings) NewGame() *environment.Game {
+	return environment.NewHandicapGame(settings.Size, settings.Komi, settings.Handicap, settings.Rules)
+}
@@ -0,0 +1,251 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+	"strconv"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/utils"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Setting of the setup menu, cycled through a fixed list of values
+type SetupOption struct {
+	Label   string
+	Values  []string
+	Index   int
+	Visible func(menu *SetupMenu) bool // nil if always visible
+}
+
+func (option *SetupOption) Value() string {
+	return option.Values[option.Index]
+}
+
+func (option *SetupOption) Select(value string) {
+	// Keeps the current value if the given one is not in the list
+	if index := slices.Index(option.Values, value); index >= 0 {
+		option.Index = index
+	}
+}
+
+func (option *SetupOption) Int() int {
+	value, _ := strconv.Atoi(option.Value())
+	return value
+}
+
+func (option *SetupOption) Float() float64 {
+	value, _ := strconv.ParseFloat(option.Value(), 64)
+	return value
+}
+
+type SetupMenu struct {
+	Options   []*SetupOption
+	Selected  int // Index of the highlighted row, len(Options) is the start button
+	KeyStates map[ebiten.Key]*utils.LockedPointer[KeyState]
+	LeftClick *utils.LockedPointer[MouseButtonState]
+	CanResume bool // Whether there is a game to go back to with Escape
+}
+
+const SetupRowHeight float32 = 26 // At scale 1
+
+// Constructor
+func NewSetupMenu(settings *GameSettings, can_resume bool) *SetupMenu {
+	var kinds_of func(player string) func(menu *SetupMenu) bool = func(player string) func(menu *SetupMenu) bool {
+		// Search settings are only shown for the search agents
+		return func(menu *SetupMenu) bool {
+			var kind string = menu.Option(player).Value()
+			return kind == "uct" || kind == "puct"
+		}
+	}
+	var simulations []string = []string{"100", "500", "1000", "2000", "5000", "10000", "20000"}
+	var routines []string = []string{"1", "2", "4", "8", "16"}
+	var menu *SetupMenu = &SetupMenu{
+		Options: []*SetupOption{
+			{Label: "Black", Values: PlayerKinds},
+			{Label: "Black simulations", Values: simulations, Visible: kinds_of("Black")},
+			{Label: "Black routines", Values: routines, Visible: kinds_of("Black")},
+			{Label: "White", Values: PlayerKinds},
+			{Label: "White simulations", Values: simulations, Visible: kinds_of("White")},
+			{Label: "White routines", Values: routines, Visible: kinds_of("White")},
+			{Label: "Board size", Values: []string{"5", "7", "9", "13", "19"}},
+			{Label: "Komi", Values: []string{"0", "0.5", "2.5", "5.5", "6.5", "7", "7.5"}},
+			{Label: "Handicap", Values: []string{"0", "2", "3", "4", "5", "6", "7", "8", "9"}},
+			{Label: "Rules", Values: []string{environment.AreaScoring.String(), environment.TerritoryScoring.String()}},
+		},
+		KeyStates: make(map[ebiten.Key]*utils.LockedPointer[KeyState]),
+		LeftClick: utils.NewLockedPointer(NewMouseButtonState(ebiten.MouseButtonLeft)),
+		CanResume: can_resume,
+	}
+	for _, key := range []ebiten.Key{ebiten.KeyUp, ebiten.KeyDown, ebiten.KeyLeft, ebiten.KeyRight, ebiten.KeyEnter} {
+		menu.KeyStates[key] = utils.NewLockedPointer(NewKeyState(key))
+	}
+
+	menu.Option("Black").Select(settings.Black.Kind)
+	menu.Option("Black simulations").Select(strconv.Itoa(settings.Black.Simulations))
+	menu.Option("Black routines").Select(strconv.Itoa(settings.Black.Routines))
+	menu.Option("White").Select(settings.White.Kind)
+	menu.Option("White simulations").Select(strconv.Itoa(settings.White.Simulations))
+	menu.Option("White routines").Select(strconv.Itoa(settings.White.Routines))
+	menu.Option("Board size").Select(strconv.Itoa(settings.Size))
+	menu.Option("Komi").Select(strconv.FormatFloat(settings.Komi, 'f', -1, 64))
+	menu.Option("Handicap").Select(strconv.Itoa(settings.Handicap))
+	menu.Option("Rules").Select(settings.Rules.String())
+	return menu
+}
+
+// Methods
+func (menu *SetupMenu) Option(label string) *SetupOption {
+	for _, option := range menu.Options {
+		if option.Label == label {
+			return option
+		}
+	}
+	panic("SetupMenu: unknown option " + label)
+}
+
+func (menu *SetupMenu) VisibleOptions() []*SetupOption {
+	var visible []*SetupOption
+	for _, option := range menu.Options {
+		if option.Visible == nil || option.Visible(menu) {
+			visible = append(visible, option)
+		}
+	}
+	return visible
+}
+
+func (menu *SetupMenu) Settings() *GameSettings {
+	rules, _ := environment.ParseRuleset(menu.Option("Rules").Value())
+	var size int = menu.Option("Board size").Int()
+	return &GameSettings{
+		Black: PlayerSettings{
+			Kind:        menu.Option("Black").Value(),
+			Simulations: menu.Option("Black simulations").Int(),
+			Routines:    menu.Option("Black routines").Int(),
+		},
+		White: PlayerSettings{
+			Kind:        menu.Option("White").Value(),
+			Simulations: menu.Option("White simulations").Int(),
+			Routines:    menu.Option("White routines").Int(),
+		},
+		Size:     size,
+		Komi:     menu.Option("Komi").Float(),
+		Handicap: min(menu.Option("Handicap").Int(), environment.MaxHandicap(size)),
+		Rules:    rules,
+	}
+}
+
+func (menu *SetupMenu) RowTop(row int, ui_metadata *UIMetadata) float32 {
+	return ui_metadata.Margin.Top + ui_metadata.Px(SetupRowHeight)*float32(row+1) // The first row is the title
+}
+
+func (menu *SetupMenu) ArrowBoxes(row int, ui_metadata *UIMetadata) (float32, float32, float32) {
+	// Left edges of the previous and next arrows, and their size
+	var size float32 = ui_metadata.Px(SetupRowHeight - 6)
+	var value_center float32 = ui_metadata.Margin.Left + 0.75*ui_metadata.BoardSize
+	return value_center - ui_metadata.Px(60) - size, value_center + ui_metadata.Px(60), size
+}
+
+func (menu *SetupMenu) StartButton(ui_metadata *UIMetadata) (float32, float32, float32, float32) {
+	var width, height float32 = ui_metadata.Px(160), ui_metadata.Px(SetupRowHeight + 8)
+	return ui_metadata.Margin.Left + (ui_metadata.BoardSize-width)/2, menu.RowTop(len(menu.Options)+1, ui_metadata), width, height
+}
+
+func (menu *SetupMenu) Update(ui_metadata *UIMetadata) bool {
+	// Returns true when the game should start
+	for _, key_state := range menu.KeyStates {
+		key_state.Get().Update()
+	}
+	menu.LeftClick.Get().Update()
+
+	var options []*SetupOption = menu.VisibleOptions()
+	menu.Selected = min(menu.Selected, len(options))
+	var change func(option *SetupOption, step int) = func(option *SetupOption, step int) {
+		option.Index = (option.Index + step + len(option.Values)) % len(option.Values)
+	}
+
+	switch {
+	case menu.KeyStates[ebiten.KeyEnter].Get().JustPressed():
+		return true
+	case menu.KeyStates[ebiten.KeyUp].Get().JustPressed():
+		menu.Selected = (menu.Selected + len(options)) % (len(options) + 1)
+	case menu.KeyStates[ebiten.KeyDown].Get().JustPressed():
+		menu.Selected = (menu.Selected + 1) % (len(options) + 1)
+	case menu.KeyStates[ebiten.KeyLeft].Get().JustPressed() && menu.Selected < len(options):
+		change(options[menu.Selected], -1)
+	case menu.KeyStates[ebiten.KeyRight].Get().JustPressed() && menu.Selected < len(options):
+		change(options[menu.Selected], 1)
+	}
+
+	if !menu.LeftClick.Get().JustPressed() {
+		return false
+	}
+	x, y := ebiten.CursorPosition()
+	if left, top, width, height := menu.StartButton(ui_metadata); IsInside(x, y, left, top, width, height) {
+		return true
+	}
+	for row, option := range options {
+		var top float32 = menu.RowTop(row, ui_metadata)
+		var previous_left, next_left, size float32 = menu.ArrowBoxes(row, ui_metadata)
+		switch {
+		case IsInside(x, y, previous_left, top+ui_metadata.Px(3), size, size):
+			change(option, -1)
+		case IsInside(x, y, next_left, top+ui_metadata.Px(3), size, size):
+			change(option, 1)
+		default:
+			continue
+		}
+		menu.Selected = row
+	}
+	return false
+}
+
+func (menu *SetupMenu) Draw(ebiten_image *ebiten.Image, ui_metadata *UIMetadata) {
+	const antialias bool = true
+	ebiten_image.Fill(color.RGBA{200, 170, 120, 255})
+	var left float32 = ui_metadata.Margin.Left
+	var center_x float64 = float64(left + ui_metadata.BoardSize/2)
+	ui_metadata.DrawText(ebiten_image, "New game", center_x, float64(ui_metadata.Margin.Top+ui_metadata.Px(SetupRowHeight)/2), text.AlignCenter)
+
+	var options []*SetupOption = menu.VisibleOptions()
+	for row, option := range options {
+		var top float32 = menu.RowTop(row, ui_metadata)
+		if row == menu.Selected {
+			vector.FillRect(ebiten_image, left-ui_metadata.Px(6), top, ui_metadata.BoardSize+ui_metadata.Px(12), ui_metadata.Px(SetupRowHeight), color.NRGBA{255, 255, 255, 70}, antialias)
+		}
+		var middle float64 = float64(top + ui_metadata.Px(SetupRowHeight)/2)
+		ui_metadata.DrawText(ebiten_image, option.Label, float64(left), middle, text.AlignStart)
+		var previous_left, next_left, size float32 = menu.ArrowBoxes(row, ui_metadata)
+		for _, box_left := range []float32{previous_left, next_left} {
+			vector.StrokeRect(ebiten_image, box_left, top+ui_metadata.Px(3), size, size, ui_metadata.Px(1), color.Black, antialias)
+		}
+		ui_metadata.DrawText(ebiten_image, "<", float64(previous_left+size/2), middle, text.AlignCenter)
+		ui_metadata.DrawText(ebiten_image, ">", float64(next_left+size/2), middle, text.AlignCenter)
+		var value string = option.Value()
+		if option.Label == "Handicap" {
+			var settings *GameSettings = menu.Settings()
+			if settings.Handicap != option.Int() {
+				value = fmt.Sprintf("%d (max)", settings.Handicap)
+			}
+		}
+		ui_metadata.DrawText(ebiten_image, value, float64(left+0.75*ui_metadata.BoardSize), middle, text.AlignCenter)
+	}
+
+	var button_left, button_top, button_width, button_height float32 = menu.StartButton(ui_metadata)
+	var button_color color.Color = color.NRGBA{255, 255, 255, 60}
+	if menu.Selected == len(options) {
+		button_color = color.NRGBA{255, 255, 255, 140}
+	}
+	vector.FillRect(ebiten_image, button_left, button_top, button_width, button_height, button_color, antialias)
+	vector.StrokeRect(ebiten_image, button_left, button_top, button_width, button_height, ui_metadata.Px(2), color.Black, antialias)
+	ui_metadata.DrawText(ebiten_image, "Start (Enter)", center_x, float64(button_top+button_height/2), text.AlignCenter)
+
+	var help string = "Arrows or clicks change the settings"
+	if menu.CanResume {
+		help += ", Escape resumes the game"
+	}
+	ui_metadata.DrawText(ebiten_image, help, center_x, float64(button_top+button_height+ui_metadata.Px(SetupRowHeight)), text.AlignCenter)
+}
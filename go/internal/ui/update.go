@@ -1,31 +1,54 @@
 package ui
 
 import (
+	"time"
+
 	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
 	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+const ResumeCheckInterval time.Duration = 20 * time.Millisecond // How often a bot move waiting for the pause or the review to end checks again
+
 func (app *App) Update() error {
 
 	// Start by updating key states
 	for _, key_state := range app.KeyStates {
 		key_state.Get().Update()
 	}
+	app.LeftClick.Get().Update()
+
+	if app.KeyStates[ebiten.KeyM].Get().JustPressed() {
+		app.ShowMoveNumbers.Set(!app.ShowMoveNumbers.Get())
+	}
+	app.HandleGraphClick()
+	if app.Replay != nil {
+		app.UpdateReplay()
+		return nil
+	}
 
 	if app.Game.Get().IsTerminal() {
+		app.StopPondering()
+		if app.KeyStates[ebiten.KeyR].Get().JustPressed() {
+			app.StartReplay()
+		}
 		return nil // Game over, no more updates needed
 	}
 
 	if app.KeyStates[ebiten.KeySpace].Get().JustPressed() {
 		app.IsPaused.Set(!app.IsPaused.Get())
 	}
+	if app.KeyStates[ebiten.KeyA].Get().JustPressed() {
+		app.ShowAnalysis.Set(!app.ShowAnalysis.Get())
+	}
 
-	var current_agent agents.Agent
+	app.HandleHumanInput()
+
+	var current_agent, waiting_agent agents.Agent
 	if app.Game.Get().Board.CurrentPlayer == environment.Black {
-		current_agent = app.BlackAgent
+		current_agent, waiting_agent = app.BlackAgent, app.WhiteAgent
 	} else {
-		current_agent = app.WhiteAgent
+		current_agent, waiting_agent = app.WhiteAgent, app.BlackAgent
 	}
 
 	select {
@@ -41,11 +64,15 @@ func (app *App) Update() error {
 			var action environment.Action = current_agent.SelectAction(game_copy)
 			app.IsThinking.Set(false)
 
-			for app.IsPaused.Get() {
-				// Wait for the space key to be pressed to play the move, this allows the user to see the move before it is played
+			if !app.PlayWhenResumed(game_copy, action) {
+				return
+			}
+			app.EvaluatePositions([]*environment.Game{game_copy})
+
+			// Keep searching while the other agent thinks, unless it is the same agent
+			if ponderer, ok := current_agent.(agents.Ponderer); ok && current_agent != waiting_agent {
+				ponderer.StartPondering(game_copy)
 			}
-			game_copy.PlayAction(action)
-			app.Game.Set(game_copy)
 		}()
 	default:
 		// Another goroutine has already initiated the move search, do nothing
@@ -53,3 +80,29 @@ func (app *App) Update() error {
 
 	return nil
 }
+
+func (app *App) PlayWhenResumed(game *environment.Game, action environment.Action) bool {
+	// Waits for the space key to be pressed and the review to be left to play the move, so that the user sees
+	// the move before it is played. False if the app is closed first.
+	for !app.Closed.Get() {
+		app.ReviewMutex.Lock()
+		if !app.IsPaused.Get() && !app.IsReviewing.Get() {
+			// No review can start before the move is shown, so none restores a game without it
+			game.PlayAction(action)
+			app.Game.Set(game)
+			app.ReviewMutex.Unlock()
+			return true
+		}
+		app.ReviewMutex.Unlock()
+		time.Sleep(ResumeCheckInterval)
+	}
+	return false
+}
+
+func (app *App) StopPondering() {
+	for _, agent := range []agents.Agent{app.BlackAgent, app.WhiteAgent} {
+		if ponderer, ok := agent.(agents.Ponderer); ok {
+			ponderer.StopPondering()
+		}
+	}
+}
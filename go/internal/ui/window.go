@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/gen/proto/remote_trainer"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/evaluator"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/sgf"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/utils"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Window is the ebiten game: it shows the setup menu or the current game, and builds a new App for each game
+type Window struct {
+	App        *App       // nil until the first game starts
+	Setup      *SetupMenu // nil while a game is shown
+	Settings   *GameSettings
+	UIMetadata *UIMetadata
+	Evaluator  remote_trainer.PositionEvaluatorClient
+	KeyStates  map[ebiten.Key]*utils.LockedPointer[KeyState]
+}
+
+// Constructor
+func NewWindow(settings *GameSettings) (*Window, error) {
+	//establish UDS connection to the position evaluation server, or evaluate in process without one
+	client, _, err := evaluator.Dial("unix:///tmp/position_evaluation.sock", evaluator.NewInfluenceBackend())
+	if err != nil {
+		return nil, err
+	}
+	var window *Window = &Window{
+		Setup:      NewSetupMenu(settings, false),
+		Settings:   settings,
+		UIMetadata: NewDefaultUI(),
+		Evaluator:  client,
+		KeyStates:  make(map[ebiten.Key]*utils.LockedPointer[KeyState]),
+	}
+	for _, key := range []ebiten.Key{ebiten.KeyN, ebiten.KeyEscape} {
+		window.KeyStates[key] = utils.NewLockedPointer(NewKeyState(key))
+	}
+	ebiten.SetWindowSize(window.UIMetadata.WindowWidth(), window.UIMetadata.WindowHeight())
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	ebiten.SetWindowTitle(window.UIMetadata.WindowTitle)
+	return window, nil
+}
+
+// Methods
+func (window *Window) NewGame() {
+	// Leaves the current game and starts one with the settings of the menu
+	window.Settings = window.Setup.Settings()
+	if window.App != nil {
+		window.App.Close()
+	}
+	window.App = InitializeApp(window.Settings, window.UIMetadata, window.Evaluator)
+	window.Setup = nil
+}
+
+func (window *Window) OpenReplay(path string) error {
+	// Shows a recorded game instead of the setup menu
+	record, err := sgf.Load(path)
+	if err != nil {
+		return err
+	}
+	app, err := NewReplayApp(record, window.UIMetadata)
+	if err != nil {
+		return err
+	}
+	if window.App != nil {
+		window.App.Close()
+	}
+	window.App = app
+	window.App.Evaluator = window.Evaluator
+	window.App.EvaluatePositions(window.App.Replay.Positions)
+	window.Setup = nil
+	return nil
+}
+
+func (window *Window) Update() error {
+	for _, key_state := range window.KeyStates {
+		key_state.Get().Update()
+	}
+	if window.Setup != nil {
+		if window.KeyStates[ebiten.KeyEscape].Get().JustPressed() && window.App != nil {
+			window.Setup = nil
+			return nil
+		}
+		if window.Setup.Update(window.UIMetadata) {
+			window.NewGame()
+		}
+		return nil
+	}
+	if window.KeyStates[ebiten.KeyN].Get().JustPressed() {
+		window.Setup = NewSetupMenu(window.Settings, true)
+		return nil
+	}
+	return window.App.Update()
+}
+
+func (window *Window) Draw(ebiten_image *ebiten.Image) {
+	if window.Setup != nil {
+		window.Setup.Draw(ebiten_image, window.UIMetadata)
+		return
+	}
+	window.App.Draw(ebiten_image)
+}
+
+func (window *Window) Layout(outside_width, outside_height int) (int, int) {
+	return window.UIMetadata.ScreenSize(outside_width, outside_height)
+}
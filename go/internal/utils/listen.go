@@ -0,0 +1,16 @@
+package utils
+
+import (
+	"net"
+	"os"
+	"strings"
+)
+
+func Listen(address string) (net.Listener, error) {
+	// unix:///path for a unix socket, host:port otherwise
+	if path, ok := strings.CutPrefix(address, "unix://"); ok {
+		os.Remove(path) // Left behind by a previous run
+		return net.Listen("unix", path)
+	}
+	return net.Listen("tcp", address)
+}
@@ -40,6 +40,12 @@ func (lv *LockedValue) Get() int {
 	return lv.value
 }
 
+func (lv *LockedValue) Set(value int) {
+	lv.mutex.Lock()
+	defer lv.mutex.Unlock()
+	lv.value = value
+}
+
 func (lv *LockedValue) Incr() {
 	lv.mutex.Lock()
 	defer lv.mutex.Unlock()
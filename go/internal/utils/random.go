@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"math"
+	"math/rand"
+)
+
+// SampleGamma draws from Gamma(alpha, 1) with the method of Marsaglia and Tsang
+func SampleGamma(rng *rand.Rand, alpha float64) float64 {
+	if alpha < 1 {
+		// Gamma(alpha) = Gamma(alpha + 1) * U^(1 / alpha)
+		return SampleGamma(rng, alpha+1) * math.Pow(rng.Float64(), 1/alpha)
+	}
+	var d float64 = alpha - 1.0/3.0
+	var c float64 = 1 / math.Sqrt(9*d)
+	for {
+		var x float64 = rng.NormFloat64()
+		var v float64 = 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		var u float64 = rng.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
+
+// SampleDirichlet draws k weights summing to 1 from the symmetric Dirichlet distribution of concentration alpha
+func SampleDirichlet(rng *rand.Rand, alpha float64, k int) []float64 {
+	var sample []float64 = make([]float64, k)
+	var sum float64 = 0
+	for i := range sample {
+		sample[i] = SampleGamma(rng, alpha)
+		sum += sample[i]
+	}
+	for i := range sample {
+		if sum > 0 {
+			sample[i] /= sum
+		} else {
+			sample[i] = 1 / float64(k)
+		}
+	}
+	return sample
+}
+
+// SampleIndex draws an index with a probability proportional to its weight
+func SampleIndex(rng *rand.Rand, weights []float64) int {
+	var sum float64 = 0
+	for _, weight := range weights {
+		sum += weight
+	}
+	var r float64 = rng.Float64() * sum
+	for i, weight := range weights {
+		r -= weight
+		if r < 0 {
+			return i
+		}
+	}
+	// Rounding errors, return the last index with a positive weight
+	for i := len(weights) - 1; i >= 0; i-- {
+		if weights[i] > 0 {
+			return i
+		}
+	}
+	return len(weights) - 1
+}
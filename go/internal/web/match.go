@@ -0,0 +1,140 @@
+package web
+
+import (
+	"time"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/utils"
+)
+
+const SearchInfoInterval time.Duration = 250 * time.Millisecond
+const GameOverPause time.Duration = 5 * time.Second // Before the next game, so that the spectators see the result
+
+// Games between two players, one after the other, shown to all the browsers connected to the server
+type Match struct {
+	NewGame    func() *environment.Game
+	Games      int // Number of games to play, 0 to play until the server stops
+	GameNumber *utils.LockedValue
+	Game       *utils.LockedPointer[environment.Game]
+	BlackAgent agents.Agent
+	WhiteAgent agents.Agent
+	BlackName  string
+	WhiteName  string
+	Closed     *utils.LockedBool
+	OnState    func()                       // Called after each move and at the start of each game
+	OnSearch   func(info agents.SearchInfo) // Called with the snapshots of the searches of the bots
+}
+
+// Constructor
+func NewMatch(new_game func() *environment.Game, games int, black_agent, white_agent agents.Agent, black_name, white_name string) *Match {
+	var match *Match = &Match{
+		NewGame:    new_game,
+		Games:      games,
+		GameNumber: utils.NewLockedValue(0),
+		Game:       utils.NewLockedPointer(new_game()),
+		BlackAgent: black_agent,
+		WhiteAgent: white_agent,
+		BlackName:  black_name,
+		WhiteName:  white_name,
+		Closed:     utils.NewLockedBool(false),
+		OnState:    func() {},
+		OnSearch:   func(info agents.SearchInfo) {},
+	}
+	for _, agent := range []agents.Agent{black_agent, white_agent} {
+		if mcts_agent, ok := agent.(*agents.MctsAgent); ok {
+			mcts_agent.InfoCandidates = 10
+			mcts_agent.SetInfoCallback(func(info agents.SearchInfo) {
+				match.OnSearch(info)
+			}, SearchInfoInterval)
+		}
+	}
+	return match
+}
+
+// Methods
+func (match *Match) Agent(player environment.Stone) agents.Agent {
+	if player == environment.White {
+		return match.WhiteAgent
+	}
+	return match.BlackAgent
+}
+
+func (match *Match) IsHuman(player environment.Stone) bool {
+	_, ok := match.Agent(player).(*agents.HumanAgent)
+	return ok
+}
+
+func (match *Match) CurrentHuman() *agents.HumanAgent {
+	// Human player whose move is awaited, nil if it is a bot's turn
+	if human, ok := match.Agent(match.Game.Get().Board.CurrentPlayer).(*agents.HumanAgent); ok && human.Waiting.Get() {
+		return human
+	}
+	return nil
+}
+
+func (match *Match) Play(action environment.Action) bool {
+	// Move of a browser for the human player to move, false if it is not expected or illegal
+	var human *agents.HumanAgent = match.CurrentHuman()
+	if human == nil {
+		return false
+	}
+	if stone, ok := action.(environment.PutStone); ok {
+		var board *environment.Board = match.Game.Get().Board
+		if stone.I < 0 || stone.I >= board.Height || stone.J < 0 || stone.J >= board.Width || !match.Game.Get().IsLegalAction(stone.I, stone.J) {
+			return false
+		}
+	}
+	return human.Play(action)
+}
+
+func (match *Match) Run() {
+	// Plays the games until the last one is over or the match is closed
+	for game_idx := 0; (match.Games == 0 || game_idx < match.Games) && !match.Closed.Get(); game_idx++ {
+		if game_idx > 0 {
+			time.Sleep(GameOverPause)
+			match.Game.Set(match.NewGame())
+		}
+		match.GameNumber.Set(game_idx + 1)
+		match.OnState()
+		match.PlayGame()
+	}
+}
+
+func (match *Match) PlayGame() {
+	for !match.Closed.Get() && !match.Game.Get().IsTerminal() {
+		var game *environment.Game = match.Game.Get().DeepCopy()
+		var agent agents.Agent = match.Agent(game.Board.CurrentPlayer)
+		var action environment.Action = agent.SelectAction(game.DeepCopy())
+		if match.Closed.Get() {
+			return
+		}
+		game.PlayAction(action)
+		match.Game.Set(game)
+		match.OnState()
+
+		// Keep searching while the other player thinks, unless it is the same agent
+		if ponderer, ok := agent.(agents.Ponderer); ok && agent != match.Agent(game.Board.CurrentPlayer) && !game.IsTerminal() {
+			ponderer.StartPondering(game)
+		}
+	}
+	match.StopPondering()
+}
+
+func (match *Match) StopPondering() {
+	for _, agent := range []agents.Agent{match.BlackAgent, match.WhiteAgent} {
+		if ponderer, ok := agent.(agents.Ponderer); ok {
+			ponderer.StopPondering()
+		}
+	}
+}
+
+func (match *Match) Close() {
+	match.Closed.Set(true)
+	match.StopPondering()
+	for _, agent := range []agents.Agent{match.BlackAgent, match.WhiteAgent} {
+		if human, ok := agent.(*agents.HumanAgent); ok {
+			human.Play(environment.Resign{}) // Unblocks PlayGame
+		}
+	}
+}
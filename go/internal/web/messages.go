@@ -0,0 +1,138 @@
+package web
+
+import (
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/render"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/sgf"
+)
+
+// Messages sent to the browsers as JSON. Points are [i, j] with row 0 at the top, stones are 0 empty, 1 black, 2 white.
+
+type Point [2]int
+
+type StateMessage struct {
+	Type       string   `json:"type"` // "state"
+	GameNumber int      `json:"game_number"`
+	Height     int      `json:"height"`
+	Width      int      `json:"width"`
+	Komi       float64  `json:"komi"`
+	Rules      string   `json:"rules"`
+	Board      [][]int  `json:"board"`
+	ToPlay     int      `json:"to_play"`
+	MoveNumber int      `json:"move_number"` // Moves played so far
+	Moves      []string `json:"moves"`       // GTP vertices of the moves played
+	LastMove   *Point   `json:"last_move"`   // Last stone still on the board, null if none
+	KoPoints   []Point  `json:"ko_points"`
+	StarPoints []Point  `json:"star_points"`
+	Captures   [2]int   `json:"captures"` // By black and by white
+	BlackName  string   `json:"black_name"`
+	WhiteName  string   `json:"white_name"`
+	BlackHuman bool     `json:"black_human"` // Whether the browsers play the moves of black
+	WhiteHuman bool     `json:"white_human"`
+	Result     string   `json:"result"` // As in SGF, for example "B+R" or "W+2.5", empty while the game goes on
+}
+
+type CandidateMessage struct {
+	Point   *Point   `json:"point"` // null for pass and resign
+	Vertex  string   `json:"vertex"`
+	Visits  int      `json:"visits"`
+	Winrate float64  `json:"winrate"` // For the player to move, in [0, 1]
+	PV      []string `json:"pv"`
+}
+
+type SearchMessage struct {
+	Type                 string             `json:"type"` // "search"
+	Player               int                `json:"player"`
+	MoveNumber           int                `json:"move_number"` // Moves played before the searched position
+	Winrate              float64            `json:"winrate"`     // Of the root, for the player to move
+	Visits               int                `json:"visits"`
+	SimulationsPerSecond float64            `json:"simulations_per_second"`
+	ElapsedSeconds       float64            `json:"elapsed_seconds"`
+	Candidates           []CandidateMessage `json:"candidates"`
+}
+
+// Message received from a browser, type is "play" with a point, "pass" or "resign"
+type ClientMessage struct {
+	Type  string `json:"type"`
+	Point Point  `json:"point"`
+}
+
+func NewStateMessage(match *Match) StateMessage {
+	var game *environment.Game = match.Game.Get()
+	var board *environment.Board = game.Board
+	var message StateMessage = StateMessage{
+		Type:       "state",
+		GameNumber: match.GameNumber.Get(),
+		Height:     board.Height,
+		Width:      board.Width,
+		Komi:       game.Komi,
+		Rules:      game.Rules.String(),
+		Board:      make([][]int, board.Height),
+		ToPlay:     int(board.CurrentPlayer),
+		MoveNumber: len(game.MoveHistory),
+		Moves:      []string{},
+		KoPoints:   []Point{},
+		StarPoints: []Point{},
+		Captures:   [2]int{board.Captures.Black, board.Captures.White},
+		BlackName:  match.BlackName,
+		WhiteName:  match.WhiteName,
+		BlackHuman: match.IsHuman(environment.Black),
+		WhiteHuman: match.IsHuman(environment.White),
+	}
+	for i := range message.Board {
+		message.Board[i] = make([]int, board.Width)
+		for j := range message.Board[i] {
+			message.Board[i][j] = int(board.Matrix[i][j])
+		}
+	}
+	for _, pos := range render.NewGeometry(0, 0, 0, board.Height, board.Width, 0, 0).StarPoints() {
+		message.StarPoints = append(message.StarPoints, Point{pos.First, pos.Second})
+	}
+	for _, action := range game.MoveHistory {
+		message.Moves = append(message.Moves, environment.GtpVertex(action, board.Height))
+	}
+	if stone, ok := render.LastStone(game, 0); ok {
+		message.LastMove = &Point{stone.I, stone.J}
+	}
+	if game.IsTerminal() {
+		message.Result = sgf.Result(game)
+	} else {
+		for _, pos := range game.KoPoints() {
+			message.KoPoints = append(message.KoPoints, Point{pos.First, pos.Second})
+		}
+	}
+	return message
+}
+
+func NewSearchMessage(info agents.SearchInfo) SearchMessage {
+	var message SearchMessage = SearchMessage{
+		Type:                 "search",
+		Player:               int(info.Player),
+		MoveNumber:           info.MoveNumber,
+		Winrate:              (info.RootValue + 1) / 2,
+		Visits:               info.RootVisits,
+		SimulationsPerSecond: info.SimulationsPerSecond,
+		ElapsedSeconds:       info.Elapsed.Seconds(),
+		Candidates:           []CandidateMessage{},
+	}
+	for _, candidate := range info.Candidates {
+		if candidate.Visits == 0 {
+			continue
+		}
+		var candidate_message CandidateMessage = CandidateMessage{
+			Vertex:  environment.GtpVertex(candidate.Action, info.BoardHeight),
+			Visits:  candidate.Visits,
+			Winrate: candidate.Winrate,
+			PV:      []string{},
+		}
+		if stone, ok := candidate.Action.(environment.PutStone); ok {
+			candidate_message.Point = &Point{stone.I, stone.J}
+		}
+		for _, action := range candidate.PV {
+			candidate_message.PV = append(candidate_message.PV, environment.GtpVertex(action, info.BoardHeight))
+		}
+		message.Candidates = append(message.Candidates, candidate_message)
+	}
+	return message
+}
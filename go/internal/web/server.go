@@ -0,0 +1,180 @@
+package web
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"sync"
+
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/agents"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/environment"
+	"github.com/TheSilentWhisperer/GoGo-power-rangers-/internal/utils"
+	"golang.org/x/net/websocket"
+)
+
+//go:embed static
+var Static embed.FS
+
+const ClientBuffer int = 64 // Messages waiting for a browser before it is considered gone
+
+// Browser connected to the server
+type Client struct {
+	Conn     *websocket.Conn
+	Messages chan []byte
+}
+
+// Server serves the page of the match and streams it to every browser over a WebSocket on /ws
+type Server struct {
+	Match      *Match
+	Mutex      sync.Mutex
+	Clients    map[*Client]bool
+	LastState  []byte // Sent to the browsers when they connect
+	LastSearch []byte
+}
+
+// Constructor
+func NewServer(match *Match) *Server {
+	var server *Server = &Server{
+		Match:   match,
+		Clients: make(map[*Client]bool),
+	}
+	match.OnState = func() {
+		server.Broadcast(NewStateMessage(match))
+	}
+	match.OnSearch = func(info agents.SearchInfo) {
+		server.Broadcast(NewSearchMessage(info))
+	}
+	server.Broadcast(NewStateMessage(match))
+	return server
+}
+
+// Methods
+func (server *Server) Broadcast(message any) {
+	// A browser that does not keep up is disconnected rather than slowing down the others
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		panic("Broadcast: " + err.Error())
+	}
+	server.Mutex.Lock()
+	defer server.Mutex.Unlock()
+	switch message.(type) {
+	case StateMessage:
+		server.LastState = encoded
+	case SearchMessage:
+		server.LastSearch = encoded
+	}
+	for client := range server.Clients {
+		select {
+		case client.Messages <- encoded:
+		default:
+			delete(server.Clients, client)
+			close(client.Messages)
+		}
+	}
+}
+
+func (server *Server) AddClient(conn *websocket.Conn) *Client {
+	var client *Client = &Client{
+		Conn:     conn,
+		Messages: make(chan []byte, ClientBuffer),
+	}
+	server.Mutex.Lock()
+	defer server.Mutex.Unlock()
+	for _, message := range [][]byte{server.LastState, server.LastSearch} {
+		if message != nil {
+			client.Messages <- message
+		}
+	}
+	server.Clients[client] = true
+	return client
+}
+
+func (server *Server) RemoveClient(client *Client) {
+	server.Mutex.Lock()
+	defer server.Mutex.Unlock()
+	if server.Clients[client] {
+		delete(server.Clients, client)
+		close(client.Messages)
+	}
+}
+
+func (client *Client) WriteMessages() {
+	// Until the server drops the client, then the connection is closed so that the read loop ends too
+	for message := range client.Messages {
+		if err := websocket.Message.Send(client.Conn, string(message)); err != nil {
+			break
+		}
+	}
+	client.Conn.Close()
+}
+
+func (server *Server) HandleWebSocket(conn *websocket.Conn) {
+	var client *Client = server.AddClient(conn)
+	defer server.RemoveClient(client)
+	go client.WriteMessages()
+	for {
+		var message ClientMessage
+		if err := websocket.JSON.Receive(conn, &message); err != nil {
+			return
+		}
+		var played bool
+		switch message.Type {
+		case "play":
+			played = server.Match.Play(environment.PutStone{I: message.Point[0], J: message.Point[1]})
+		case "pass":
+			played = server.Match.Play(environment.Pass{})
+		case "resign":
+			played = server.Match.Play(environment.Resign{})
+		}
+		if !played {
+			// Tells the browser its move was refused, with the position it should have played on
+			server.Mutex.Lock()
+			if server.Clients[client] {
+				select {
+				case client.Messages <- server.LastState:
+				default:
+				}
+			}
+			server.Mutex.Unlock()
+		}
+	}
+}
+
+func CheckOrigin(config *websocket.Config, request *http.Request) error {
+	// Only the page served here may connect: any other site open in the browser could otherwise watch and play
+	origin, err := websocket.Origin(config, request)
+	if err != nil {
+		return err
+	}
+	if origin == nil || origin.Host != request.Host {
+		return errors.New("cross-origin WebSocket connection refused")
+	}
+	config.Origin = origin
+	return nil
+}
+
+func (server *Server) Handler() (http.Handler, error) {
+	static, err := fs.Sub(Static, "static")
+	if err != nil {
+		return nil, err
+	}
+	var mux *http.ServeMux = http.NewServeMux()
+	mux.Handle("/", http.FileServerFS(static))
+	mux.Handle("/ws", websocket.Server{Handler: server.HandleWebSocket, Handshake: CheckOrigin})
+	return mux, nil
+}
+
+func (server *Server) ListenAndServe(address string) error {
+	// host:port, or unix:///path for a unix socket
+	handler, err := server.Handler()
+	if err != nil {
+		return err
+	}
+	listener, err := utils.Listen(address)
+	if err != nil {
+		return err
+	}
+	return http.Serve(listener, handler)
+}